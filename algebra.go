@@ -0,0 +1,583 @@
+package expr
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/skillian/errors"
+)
+
+// Derive gets the derivative of e with respect to x.  x is usually a Var
+// but it can be any expression, such as an Attr:  Every subexpression of e
+// that is the same as x according to SameExpr is differentiated as the
+// variable and every other Var and Attr is a constant.
+//
+// Add, Sub, Mul, Div and Pow expressions can be differentiated, but the
+// exponents of Pows must not depend on x.  Multiplications by zero and one
+// and additions of zero are left out of the result, but it is otherwise not
+// simplified.  Use Expand and CollectTerms to simplify it.
+func Derive(e ValueExpr, x ValueExpr) (ValueExpr, error) {
+	if SameExpr(e, x) {
+		return Int(1), nil
+	}
+	if !dependsOn(e, x) {
+		return Int(0), nil
+	}
+	var operands [2]ValueExpr
+	switch e := e.(type) {
+	case Add:
+		operands = e
+	case Sub:
+		operands = e
+	case Mul:
+		operands = e
+	case Div:
+		operands = e
+	case Pow:
+		if dependsOn(e[1], x) {
+			return nil, errors.Errorf(
+				"cannot differentiate %v with respect to %v: the exponent depends on it",
+				e, x)
+		}
+		du, err := Derive(e[0], x)
+		if err != nil {
+			return nil, err
+		}
+		// d(u ** n) = n * u ** (n - 1) * du
+		return product(product(e[1], power(e[0], difference(e[1], Int(1)))), du), nil
+	default:
+		return nil, errors.Errorf(
+			"cannot differentiate %v with respect to %v", e, x)
+	}
+	var ds [2]ValueExpr
+	for i, operand := range operands {
+		d, err := Derive(operand, x)
+		if err != nil {
+			return nil, err
+		}
+		ds[i] = d
+	}
+	u, v := operands[0], operands[1]
+	switch e.(type) {
+	case Add:
+		return sum(ds[0], ds[1]), nil
+	case Sub:
+		return difference(ds[0], ds[1]), nil
+	case Mul:
+		return sum(product(ds[0], v), product(u, ds[1])), nil
+	}
+	// d(u / v) = (du * v - u * dv) / v ** 2
+	if isZero(ds[1]) {
+		return quotient(ds[0], v), nil
+	}
+	return quotient(
+		difference(product(ds[0], v), product(u, ds[1])),
+		power(v, Int(2))), nil
+}
+
+// dependsOn checks if x is a subexpression of e.
+func dependsOn(e, x Expr) bool {
+	if SameExpr(e, x) {
+		return true
+	}
+	for _, operand := range Operands(e) {
+		if dependsOn(operand, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand distributes multiplications and divisions over additions and
+// subtractions and multiplies out sums raised to constant non-negative
+// integer powers, so that every Add and Sub is above every Mul, Div and Pow.
+//
+// Expanding can grow the expression exponentially, so a *LimitError is
+// returned if the result would have more than limit terms.  If limit is 0,
+// the number of terms is unlimited.
+func Expand(e Expr, limit int) (Expr, error) {
+	var err error
+	e = mapOperands(e, func(operand Expr) Expr {
+		if err != nil {
+			return operand
+		}
+		expanded, err2 := Expand(operand, limit)
+		if err2 != nil {
+			err = err2
+			return operand
+		}
+		return expanded
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch x := e.(type) {
+	case Mul:
+		if limit > 0 && countTerms(x[0])*countTerms(x[1]) > limit {
+			return nil, &LimitError{Kind: TermLimit, Max: limit, Expr: e}
+		}
+		if l, r, sub, ok := sumOperands(x[0]); ok {
+			return expandSum(Mul{l, x[1]}, Mul{r, x[1]}, sub, limit)
+		}
+		if l, r, sub, ok := sumOperands(x[1]); ok {
+			return expandSum(Mul{x[0], l}, Mul{x[0], r}, sub, limit)
+		}
+	case Div:
+		if l, r, sub, ok := sumOperands(x[0]); ok {
+			return expandSum(Div{l, x[1]}, Div{r, x[1]}, sub, limit)
+		}
+	case Pow:
+		n, ok := intOf(x[1])
+		if _, _, _, isSum := sumOperands(x[0]); !ok || !isSum || n < 0 {
+			break
+		}
+		if n == 0 {
+			return Int(1), nil
+		}
+		// Check the limit before multiplying because every
+		// multiplication at least doubles the number of terms:
+		for i, terms := int64(1), countTerms(x[0]); limit > 0 && i < n; i++ {
+			if terms *= countTerms(x[0]); terms > limit {
+				return nil, &LimitError{Kind: TermLimit, Max: limit, Expr: e}
+			}
+		}
+		var result Expr = x[0]
+		for i := int64(1); i < n; i++ {
+			if result, err = Expand(Mul{result.(ValueExpr), x[0]}, limit); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	return e, nil
+}
+
+// ExpandMapper creates a Mapper that expands expressions.  Expressions that
+// would have more than limit terms are left as they are.
+func ExpandMapper(limit int) Mapper {
+	return func(e Expr) Expr {
+		expanded, err := Expand(e, limit)
+		if err != nil {
+			return e
+		}
+		return expanded
+	}
+}
+
+// expandSum expands the terms of a sum and combines them into an Add or, if
+// sub is true, a Sub.
+func expandSum(left, right Expr, sub bool, limit int) (Expr, error) {
+	l, err := Expand(left, limit)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Expand(right, limit)
+	if err != nil {
+		return nil, err
+	}
+	return combineSum(l, r, sub), nil
+}
+
+// countTerms counts the terms of a sum.
+func countTerms(e Expr) int {
+	if l, r, _, ok := sumOperands(e); ok {
+		return countTerms(l) + countTerms(r)
+	}
+	return 1
+}
+
+// sumOperands gets the operands of an Add or a Sub.
+func sumOperands(e Expr) (left, right ValueExpr, sub, ok bool) {
+	switch e := e.(type) {
+	case Add:
+		return e[0], e[1], false, true
+	case Sub:
+		return e[0], e[1], true, true
+	}
+	return nil, nil, false, false
+}
+
+// combineSum creates an Add or, if sub is true, a Sub of the expressions.
+func combineSum(left, right Expr, sub bool) Expr {
+	if sub {
+		return Sub{left.(ValueExpr), right.(ValueExpr)}
+	}
+	return Add{left.(ValueExpr), right.(ValueExpr)}
+}
+
+// mapOperands creates a copy of e with f applied to its operands.  Unlike
+// Copy, Vars are kept as they are.
+func mapOperands(e Expr, f Mapper) Expr {
+	operands := Operands(e)
+	if len(operands) == 0 {
+		return e
+	}
+	for i, operand := range operands {
+		operands[i] = f(operand)
+	}
+	mapped, err := WithOperands(e, operands)
+	if err != nil {
+		return e
+	}
+	return mapped
+}
+
+// CollectTerms combines the like terms of sums into single terms with
+// rational coefficients (e.g. 2 * x * y + y * x - 3 becomes 3 * x * y - 3).
+// Products are combined into powers and constants are folded.  Terms are
+// in the order that they first appear in and so are the factors within
+// them.  CollectTerms doesn't distribute products over sums, so it is
+// usually used after Expand.  Terms cancel out even where they are undefined
+// (e.g. y / x - y / x becomes 0 even though it can't be evaluated when x is
+// 0).  CollectTerms can be used as a Mapper.
+func CollectTerms(e Expr) Expr {
+	switch e.(type) {
+	case Add, Sub, Mul, Div, Pow:
+		return polynomialOf(e.(ValueExpr)).expr()
+	}
+	return mapOperands(e, CollectTerms)
+}
+
+// polynomial is a sum of terms.
+type polynomial []monomial
+
+// monomial is a rational coefficient times a product of factors.
+type monomial struct {
+	coef    *big.Rat
+	factors []factor
+}
+
+// factor is an expression raised to an integer power.
+type factor struct {
+	base ValueExpr
+	exp  int64
+}
+
+// polynomialOf gets the collected terms of e.
+func polynomialOf(e ValueExpr) polynomial {
+	var p polynomial
+	p.add(e, big.NewRat(1, 1))
+	return p
+}
+
+// add adds e times coef to the polynomial.
+func (p *polynomial) add(e ValueExpr, coef *big.Rat) {
+	switch e := e.(type) {
+	case Add:
+		p.add(e[0], coef)
+		p.add(e[1], coef)
+		return
+	case Sub:
+		p.add(e[0], coef)
+		p.add(e[1], new(big.Rat).Neg(coef))
+		return
+	}
+	m := monomialOf(e)
+	m.coef.Mul(m.coef, coef)
+	for i := range *p {
+		if (*p)[i].like(m) {
+			(*p)[i].coef.Add((*p)[i].coef, m.coef)
+			return
+		}
+	}
+	*p = append(*p, m)
+}
+
+// monomialOf gets e as a single term.  Sums that don't collect into a single
+// term are factors.
+func monomialOf(e ValueExpr) monomial {
+	m := monomial{coef: big.NewRat(1, 1)}
+	if r, ok := constRat(e); ok {
+		m.coef.Set(r)
+		return m
+	}
+	switch x := e.(type) {
+	case Add, Sub:
+		p := polynomialOf(x)
+		p = p.nonzero()
+		switch len(p) {
+		case 0:
+			m.coef.SetInt64(0)
+			return m
+		case 1:
+			return p[0]
+		}
+		m.factors = []factor{{p.expr(), 1}}
+		return m
+	case Mul:
+		m = monomialOf(x[0])
+		if m.mul(monomialOf(x[1]), 1) {
+			return m
+		}
+		m = monomial{coef: big.NewRat(1, 1)}
+	case Div:
+		if r, ok := constRat(x[1]); ok && r.Sign() != 0 {
+			m = monomialOf(x[0])
+			m.coef.Quo(m.coef, r)
+			return m
+		}
+	case Pow:
+		if n, ok := intOf(x[1]); ok && n >= 0 && m.mul(monomialOf(x[0]), n) {
+			return m
+		}
+	}
+	m.factors = []factor{{mapOperands(e, CollectTerms).(ValueExpr), 1}}
+	return m
+}
+
+// maxCoefBits is the largest size of a coefficient that CollectTerms
+// computes by raising another coefficient to a power.  Larger powers are
+// left as they are.
+const maxCoefBits = 1 << 16
+
+// mul multiplies m by o raised to the nth power.  If the coefficient or the
+// exponents of the result would be too large, m is left as it is and mul
+// returns false.
+func (m *monomial) mul(o monomial, n int64) bool {
+	coef, ok := ratPow(o.coef, n)
+	if !ok {
+		return false
+	}
+	factors := append([]factor(nil), m.factors...)
+factors:
+	for _, f := range o.factors {
+		if f.exp != 0 && n > math.MaxInt64/f.exp {
+			return false
+		}
+		f.exp *= n
+		for i, g := range factors {
+			if SameExpr(f.base, g.base) {
+				if f.exp > math.MaxInt64-g.exp {
+					return false
+				}
+				factors[i].exp += f.exp
+				continue factors
+			}
+		}
+		factors = append(factors, f)
+	}
+	m.coef.Mul(m.coef, coef)
+	m.factors = factors
+	return true
+}
+
+// ratPow raises r to the non-negative power n.  ok is false if the result
+// would have more than maxCoefBits bits.
+func ratPow(r *big.Rat, n int64) (result *big.Rat, ok bool) {
+	num, den := new(big.Int).Abs(r.Num()), r.Denom()
+	if num.Sign() == 0 || (num.IsInt64() && num.Int64() == 1 && den.IsInt64() && den.Int64() == 1) {
+		// 0, 1 and -1 stay small no matter the power:
+		if n == 0 {
+			return big.NewRat(1, 1), true
+		}
+		if r.Sign() < 0 && n%2 == 0 {
+			return big.NewRat(1, 1), true
+		}
+		return new(big.Rat).Set(r), true
+	}
+	if bits := int64(num.BitLen() + den.BitLen()); n > maxCoefBits/bits {
+		return nil, false
+	}
+	e := big.NewInt(n)
+	return new(big.Rat).SetFrac(
+		new(big.Int).Exp(r.Num(), e, nil),
+		new(big.Int).Exp(den, e, nil)), true
+}
+
+// like checks if m and o have the same factors raised to the same powers.
+func (m monomial) like(o monomial) bool {
+	a, b := m.powers(), o.powers()
+	if len(a) != len(b) {
+		return false
+	}
+factors:
+	for _, f := range a {
+		for _, g := range b {
+			if f.exp == g.exp && SameExpr(f.base, g.base) {
+				continue factors
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// powers gets the factors of m without exponents of zero.
+func (m monomial) powers() []factor {
+	fs := make([]factor, 0, len(m.factors))
+	for _, f := range m.factors {
+		if f.exp != 0 {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+// nonzero gets the terms of the polynomial with nonzero coefficients.
+func (p polynomial) nonzero() polynomial {
+	nz := make(polynomial, 0, len(p))
+	for _, m := range p {
+		if m.coef.Sign() != 0 {
+			nz = append(nz, m)
+		}
+	}
+	return nz
+}
+
+// expr creates an expression of the polynomial.  Terms with negative
+// coefficients after the first are subtracted.
+func (p polynomial) expr() ValueExpr {
+	var result ValueExpr
+	for _, m := range p.nonzero() {
+		if result == nil {
+			result = m.expr()
+			continue
+		}
+		if m.coef.Sign() < 0 {
+			m.coef = new(big.Rat).Neg(m.coef)
+			result = Sub{result, m.expr()}
+			continue
+		}
+		result = Add{result, m.expr()}
+	}
+	if result == nil {
+		return Int(0)
+	}
+	return result
+}
+
+// expr creates an expression of the coefficient times the factors.
+func (m monomial) expr() ValueExpr {
+	var result ValueExpr
+	for _, f := range m.powers() {
+		var e ValueExpr = f.base
+		if f.exp != 1 {
+			e = Pow{e, Int(f.exp)}
+		}
+		if result == nil {
+			result = e
+			continue
+		}
+		result = Mul{result, e}
+	}
+	switch {
+	case result == nil:
+		return ratValue(m.coef)
+	case m.coef.Cmp(big.NewRat(1, 1)) == 0:
+		return result
+	}
+	return Mul{ratValue(m.coef), result}
+}
+
+// constRat gets the value of a numeric constant.
+func constRat(e Expr) (*big.Rat, bool) {
+	n, ok := e.(Number)
+	if !ok || !IsConst(e) {
+		return nil, false
+	}
+	r := new(big.Rat)
+	if !ratOf(n, r) {
+		return nil, false
+	}
+	return r, true
+}
+
+// intOf gets the value of an integer constant.
+func intOf(e Expr) (int64, bool) {
+	r, ok := constRat(e)
+	if !ok || !r.IsInt() || !r.Num().IsInt64() {
+		return 0, false
+	}
+	return r.Num().Int64(), true
+}
+
+// ratValue gets r as an Int if it is an integer or as a Rational otherwise.
+func ratValue(r *big.Rat) ValueExpr {
+	if r.IsInt() && r.Num().IsInt64() {
+		return Int(r.Num().Int64())
+	}
+	return (*Rational)(new(big.Rat).Set(r))
+}
+
+func isZero(e Expr) bool {
+	r, ok := constRat(e)
+	return ok && r.Sign() == 0
+}
+
+func isOne(e Expr) bool {
+	r, ok := constRat(e)
+	return ok && r.Cmp(big.NewRat(1, 1)) == 0
+}
+
+// sum, difference, product, quotient and power create arithmetic
+// expressions, leaving out additions of zero and multiplications by zero and
+// one and folding constants.
+
+func sum(a, b ValueExpr) ValueExpr {
+	switch {
+	case isZero(a):
+		return b
+	case isZero(b):
+		return a
+	}
+	return foldConst(Add{a, b})
+}
+
+func difference(a, b ValueExpr) ValueExpr {
+	switch {
+	case isZero(b):
+		return a
+	case isZero(a):
+		return product(Int(-1), b)
+	}
+	return foldConst(Sub{a, b})
+}
+
+func product(a, b ValueExpr) ValueExpr {
+	switch {
+	case isZero(a) || isZero(b):
+		return Int(0)
+	case isOne(a):
+		return b
+	case isOne(b):
+		return a
+	}
+	return foldConst(Mul{a, b})
+}
+
+func quotient(a, b ValueExpr) ValueExpr {
+	switch {
+	case isZero(a):
+		return Int(0)
+	case isOne(b):
+		return a
+	}
+	return foldConst(Div{a, b})
+}
+
+func power(a, b ValueExpr) ValueExpr {
+	switch {
+	case isZero(b):
+		return Int(1)
+	case isOne(b):
+		return a
+	}
+	return foldConst(Pow{a, b})
+}
+
+// foldConst evaluates e if its operands are numeric constants.
+func foldConst(e ValueExpr) ValueExpr {
+	for _, operand := range Operands(e) {
+		if _, ok := constRat(operand); !ok {
+			return e
+		}
+	}
+	v, err := e.EvalValue()
+	if err != nil {
+		return e
+	}
+	if r, ok := constRat(v); ok {
+		return ratValue(r)
+	}
+	return e
+}
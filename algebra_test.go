@@ -0,0 +1,237 @@
+package expr_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+// sameValues checks if a and b evaluate to equal values for every
+// combination of values of the vars where b can be evaluated.
+func sameValues(t *testing.T, a, b expr.ValueExpr, vars []*expr.Int, values ...expr.Int) {
+	t.Helper()
+	var assign func(i int)
+	assign = func(i int) {
+		if i < len(vars) {
+			for _, v := range values {
+				*vars[i] = v
+				assign(i + 1)
+			}
+			return
+		}
+		bv, err := b.EvalValue()
+		if err != nil {
+			return
+		}
+		av, err := a.EvalValue()
+		if err != nil {
+			t.Errorf("%v: %v (%v = %v)", a, err, b, bv)
+			return
+		}
+		if eq, err := expr.EqualValues(av, bv); err != nil || !eq {
+			t.Errorf("%v = %v but %v = %v", a, av, b, bv)
+		}
+	}
+	assign(0)
+}
+
+func rat(a, b int64) *expr.Rational {
+	return (*expr.Rational)(big.NewRat(a, b))
+}
+
+func TestDerive(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	vars := []*expr.Int{&x, &y}
+	tcs := []struct {
+		e, expect expr.ValueExpr
+	}{
+		{expr.Int(5), expr.Int(0)},
+		{&x, expr.Int(1)},
+		{&y, expr.Int(0)},
+		{expr.Add{&x, &y}, expr.Int(1)},
+		{expr.Mul{expr.Int(3), &x}, expr.Int(3)},
+		{expr.Mul{&x, &y}, &y},
+		{expr.Pow{&x, expr.Int(3)}, expr.Mul{expr.Int(3), expr.Pow{&x, expr.Int(2)}}},
+		{
+			// x ** 3 + 2 * x * y - y / x
+			expr.Sub{
+				expr.Add{expr.Pow{&x, expr.Int(3)}, expr.Mul{expr.Mul{expr.Int(2), &x}, &y}},
+				expr.Div{&y, &x},
+			},
+			// 3 * x ** 2 + 2 * y + y / x ** 2
+			expr.Add{
+				expr.Add{expr.Mul{expr.Int(3), expr.Pow{&x, expr.Int(2)}}, expr.Mul{expr.Int(2), &y}},
+				expr.Div{&y, expr.Pow{&x, expr.Int(2)}},
+			},
+		},
+		{
+			expr.Div{expr.Pow{expr.Sub{&x, &y}, expr.Int(2)}, expr.Int(4)},
+			expr.Div{expr.Sub{&x, &y}, expr.Int(2)},
+		},
+		{expr.Pow{&y, &y}, expr.Int(0)},
+		{expr.Mod{&y, expr.Int(3)}, expr.Int(0)},
+	}
+	for _, tc := range tcs {
+		d, err := expr.Derive(tc.e, &x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sameValues(t, d, tc.expect, vars, -3, -1, 1, 2, 5)
+	}
+	for _, e := range []expr.ValueExpr{
+		expr.Pow{expr.Int(2), &x},
+		expr.Mod{&x, expr.Int(3)},
+	} {
+		if d, err := expr.Derive(e, &x); err == nil {
+			t.Errorf("expected differentiating %v to fail but got %v", e, d)
+		}
+	}
+}
+
+func TestDeriveAttr(t *testing.T) {
+	t.Parallel()
+	order := expr.ValueOf(&struct{ Price, Qty int }{Price: 10, Qty: 3})
+	price := expr.Attr{order, "Price"}
+	qty := expr.Attr{order, "Qty"}
+	total := expr.Mul{expr.Mul{price, qty}, expr.Sub{expr.Int(1), expr.Div{qty, expr.Int(100)}}}
+	d, err := expr.Derive(total, price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = expr.CollectTerms(expand(t, d)).(expr.ValueExpr)
+	// d/dPrice (Price * Qty - Price * Qty ** 2 / 100) = Qty - Qty ** 2 / 100:
+	expect := expr.Sub{qty, expr.Mul{rat(1, 100), expr.Pow{qty, expr.Int(2)}}}
+	if !expr.SameExpr(d, expect) {
+		t.Errorf("expected %v but got %v", expect, d)
+	}
+	v, err := d.EvalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eq, err := expr.EqualValues(v, rat(291, 100)); err != nil || !eq {
+		t.Errorf("expected 2.91 but got %v", v)
+	}
+}
+
+func TestExpandCollectTerms(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	vars := []*expr.Int{&x, &y}
+	two := expr.Int(2)
+	tcs := []struct {
+		e      expr.ValueExpr
+		expect expr.ValueExpr
+	}{
+		{expr.Add{&x, &x}, expr.Mul{two, &x}},
+		{expr.Sub{&x, &x}, expr.Int(0)},
+		{expr.Mul{&x, &x}, expr.Pow{&x, two}},
+		{expr.Mul{expr.Mul{two, &x}, expr.Mul{&y, expr.Int(3)}}, expr.Mul{expr.Int(6), expr.Mul{&x, &y}}},
+		{
+			expr.Sub{expr.Add{expr.Mul{expr.Mul{two, &x}, &y}, expr.Mul{&y, &x}}, expr.Int(3)},
+			expr.Sub{expr.Mul{expr.Int(3), expr.Mul{&x, &y}}, expr.Int(3)},
+		},
+		{
+			expr.Pow{expr.Add{&x, expr.Int(1)}, two},
+			expr.Add{expr.Add{expr.Pow{&x, two}, expr.Mul{two, &x}}, expr.Int(1)},
+		},
+		{
+			expr.Mul{expr.Sub{&x, &y}, expr.Add{&x, &y}},
+			expr.Sub{expr.Pow{&x, two}, expr.Pow{&y, two}},
+		},
+		{
+			expr.Div{expr.Add{expr.Mul{two, &x}, expr.Int(1)}, expr.Int(4)},
+			expr.Add{expr.Mul{rat(1, 2), &x}, rat(1, 4)},
+		},
+		{
+			expr.Sub{expr.Div{&y, expr.Add{&x, &x}}, expr.Div{&y, expr.Mul{two, &x}}},
+			expr.Int(0),
+		},
+		{expr.Pow{expr.Sub{&x, &y}, expr.Int(0)}, expr.Int(1)},
+	}
+	for _, tc := range tcs {
+		actual := expr.CollectTerms(expand(t, tc.e)).(expr.ValueExpr)
+		if !expr.SameExpr(actual, tc.expect) {
+			t.Errorf("%v: expected %v but got %v", tc.e, tc.expect, actual)
+		}
+		sameValues(t, actual, tc.e, vars, -2, 0, 1, 3)
+	}
+	// Sums that aren't expanded are collected as single factors:
+	sum := expr.Add{&x, &y}
+	actual := expr.CollectTerms(expr.Add{expr.Mul{sum, sum}, expr.Pow{expr.Add{&x, &y}, two}})
+	if expect := (expr.Mul{two, expr.Pow{sum, two}}); !expr.SameExpr(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+	// Expand and CollectTerms work as Mappers on expressions without
+	// variables:
+	e := expr.Mul{expr.Add{expr.Int(1), expr.Int(2)}, expr.Int(3)}
+	if actual := expr.Simplify(e, expr.ExpandMapper(0), expr.CollectTerms); !expr.SameExpr(actual, expr.Int(9)) {
+		t.Errorf("expected 9 but got %v", actual)
+	}
+}
+
+func expand(t *testing.T, e expr.Expr) expr.Expr {
+	t.Helper()
+	expanded, err := expr.Expand(e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expanded
+}
+
+func TestExpandLimit(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	one := expr.Int(1)
+	tcs := []struct {
+		e     expr.Expr
+		limit int
+		ok    bool
+	}{
+		{expr.Pow{expr.Add{&x, one}, expr.Int(1 << 20)}, 1000, false},
+		{expr.Pow{expr.Add{&x, one}, expr.Int(3)}, 8, true},
+		{expr.Pow{expr.Add{&x, one}, expr.Int(4)}, 8, false},
+		{expr.Mul{expr.Add{&x, one}, expr.Sub{&y, one}}, 4, true},
+		{expr.Mul{expr.Add{&x, one}, expr.Add{&y, expr.Add{&x, one}}}, 5, false},
+		{expr.Not{expr.Eq{expr.Mul{expr.Add{&x, one}, expr.Add{&y, one}}, expr.Int(0)}}, 3, false},
+	}
+	for _, tc := range tcs {
+		_, err := expr.Expand(tc.e, tc.limit)
+		if tc.ok {
+			if err != nil {
+				t.Errorf("%v: %v", tc.e, err)
+			}
+			continue
+		}
+		var le *expr.LimitError
+		if !errors.As(err, &le) || le.Kind != expr.TermLimit {
+			t.Errorf("%v: expected *LimitError but got %v", tc.e, err)
+		}
+	}
+	e := expr.Pow{expr.Add{&x, one}, expr.Int(1 << 20)}
+	if actual := expr.ExpandMapper(1000)(e); !expr.SameExpr(actual, e) {
+		t.Errorf("expected %v to be left as it is but got %v", e, actual)
+	}
+}
+
+func TestCollectTermsLargePowers(t *testing.T) {
+	t.Parallel()
+	var x expr.Int
+	huge := expr.Int(1 << 40)
+	tcs := []struct {
+		e, expect expr.ValueExpr
+	}{
+		{expr.Pow{&x, huge}, expr.Pow{&x, huge}},
+		{expr.Pow{expr.Mul{expr.Int(-1), &x}, huge}, expr.Pow{&x, huge}},
+		{expr.Pow{expr.Mul{expr.Int(2), &x}, huge}, expr.Pow{expr.Mul{expr.Int(2), &x}, huge}},
+		{expr.Pow{expr.Pow{&x, huge}, huge}, expr.Pow{expr.Pow{&x, huge}, huge}},
+		{expr.Pow{expr.Mul{expr.Int(2), &x}, expr.Int(3)}, expr.Mul{expr.Int(8), expr.Pow{&x, expr.Int(3)}}},
+	}
+	for _, tc := range tcs {
+		if actual := expr.CollectTerms(tc.e); !expr.SameExpr(actual, tc.expect) {
+			t.Errorf("%v: expected %v but got %v", tc.e, tc.expect, actual)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package expr
 
 import (
-	"github.com/skillian/errors"
+	"context"
 )
 
 type binaryValue [2]ValueExpr
@@ -34,6 +34,13 @@ type EvalAdder interface {
 	EvalAdd(v Value) (Value, error)
 }
 
+// ContextEvalAdder is an EvalAdder whose addition depends on evaluation
+// options stored in the context (such as the IntMode).
+type ContextEvalAdder interface {
+	EvalAdder
+	EvalAddContext(ctx context.Context, v Value) (Value, error)
+}
+
 // Copy the expression.
 func (a Add) Copy(transformations ...Mapper) Expr {
 	return ApplyMappers(Add(binaryValue(a).copy(transformations...)), transformations...)
@@ -50,16 +57,30 @@ func (a Add) Eval() (interface{}, error) {
 
 // EvalValue evaluates the expression to a value.
 func (a Add) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), a)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (a Add) EvalValueContext(ctx context.Context) (Value, error) {
 	var operands [2]Value
-	err := EvalValueInto(a[:], operands[:])
+	err := evalValuesContext(ctx, a[:], operands[:])
 	if err != nil {
 		return nil, err
 	}
-	adder, ok := operands[0].(EvalAdder)
+	return evalAdd(ctx, operands[0], operands[1])
+}
+
+// evalAdd adds right to left.
+func evalAdd(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if adder, ok := left.(ContextEvalAdder); ok {
+		return adder.EvalAddContext(ctx, right)
+	}
+	adder, ok := left.(EvalAdder)
 	if !ok {
-		return nil, errors.Errorf("cannot add %v to %v", operands[0], operands[1])
+		return nil, typeMismatch("add", left, right)
 	}
-	return adder.EvalAdd(operands[1])
+	return adder.EvalAdd(right)
 }
 
 // Left gets the left side of the binary expression.
@@ -88,6 +109,13 @@ type EvalSubtracter interface {
 	EvalSubtract(v Value) (Value, error)
 }
 
+// ContextEvalSubtracter is an EvalSubtracter whose subtraction depends on
+// evaluation options stored in the context.
+type ContextEvalSubtracter interface {
+	EvalSubtracter
+	EvalSubtractContext(ctx context.Context, v Value) (Value, error)
+}
+
 // Copy the expression.
 func (s Sub) Copy(transformations ...Mapper) Expr {
 	return ApplyMappers(Sub(binaryValue(s).copy(transformations...)), transformations...)
@@ -104,16 +132,30 @@ func (s Sub) Eval() (interface{}, error) {
 
 // EvalValue evaluates the expression to a value.
 func (s Sub) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), s)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (s Sub) EvalValueContext(ctx context.Context) (Value, error) {
 	var operands [2]Value
-	err := EvalValueInto(s[:], operands[:])
+	err := evalValuesContext(ctx, s[:], operands[:])
 	if err != nil {
 		return nil, err
 	}
-	subber, ok := operands[0].(EvalSubtracter)
+	return evalSubtract(ctx, operands[0], operands[1])
+}
+
+// evalSubtract subtracts right from left.
+func evalSubtract(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if subber, ok := left.(ContextEvalSubtracter); ok {
+		return subber.EvalSubtractContext(ctx, right)
+	}
+	subber, ok := left.(EvalSubtracter)
 	if !ok {
-		return nil, errors.Errorf("cannot subtract %v from %v", operands[0], operands[1])
+		return nil, typeMismatch("subtract", left, right)
 	}
-	return subber.EvalSubtract(operands[1])
+	return subber.EvalSubtract(right)
 }
 
 // Left side of the binary expression.
@@ -128,7 +170,7 @@ func (s Sub) Right() Expr {
 
 // String represents the expression as a string.
 func (s Sub) String() string {
-	return stringifyBinaryInfixHelper(s, "+")
+	return stringifyBinaryInfixHelper(s, "-")
 }
 
 // Multiplication binary expression
@@ -142,6 +184,13 @@ type EvalMultiplier interface {
 	EvalMultiply(v Value) (Value, error)
 }
 
+// ContextEvalMultiplier is an EvalMultiplier whose multiplication depends on
+// evaluation options stored in the context.
+type ContextEvalMultiplier interface {
+	EvalMultiplier
+	EvalMultiplyContext(ctx context.Context, v Value) (Value, error)
+}
+
 // Copy the expression.
 func (m Mul) Copy(transformations ...Mapper) Expr {
 	return ApplyMappers(Mul(binaryValue(m).copy(transformations...)), transformations...)
@@ -158,16 +207,30 @@ func (m Mul) Eval() (interface{}, error) {
 
 // EvalValue evaluates the expression to a value.
 func (m Mul) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), m)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (m Mul) EvalValueContext(ctx context.Context) (Value, error) {
 	var operands [2]Value
-	err := EvalValueInto(m[:], operands[:])
+	err := evalValuesContext(ctx, m[:], operands[:])
 	if err != nil {
 		return nil, err
 	}
-	multiplier, ok := operands[0].(EvalMultiplier)
+	return evalMultiply(ctx, operands[0], operands[1])
+}
+
+// evalMultiply multiplies left by right.
+func evalMultiply(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if multiplier, ok := left.(ContextEvalMultiplier); ok {
+		return multiplier.EvalMultiplyContext(ctx, right)
+	}
+	multiplier, ok := left.(EvalMultiplier)
 	if !ok {
-		return nil, errors.Errorf("cannot multiply %v to %v", operands[0], operands[1])
+		return nil, typeMismatch("multiply", left, right)
 	}
-	return multiplier.EvalMultiply(operands[1])
+	return multiplier.EvalMultiply(right)
 }
 
 // Left side of the binary expression.
@@ -196,6 +259,13 @@ type EvalDivider interface {
 	EvalDivide(v Value) (Value, error)
 }
 
+// ContextEvalDivider is an EvalDivider whose division depends on evaluation
+// options stored in the context (such as the DivisionMode).
+type ContextEvalDivider interface {
+	EvalDivider
+	EvalDivideContext(ctx context.Context, v Value) (Value, error)
+}
+
 // Copy the expression.
 func (d Div) Copy(transformations ...Mapper) Expr {
 	return ApplyMappers(Div(binaryValue(d).copy(transformations...)), transformations...)
@@ -212,17 +282,28 @@ func (d Div) Eval() (interface{}, error) {
 
 // EvalValue evaluates the expression to a value.
 func (d Div) EvalValue() (Value, error) {
-	left, err := d[0].EvalValue()
+	return EvalContext(context.Background(), d)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (d Div) EvalValueContext(ctx context.Context) (Value, error) {
+	var operands [2]Value
+	err := evalValuesContext(ctx, d[:], operands[:])
 	if err != nil {
 		return nil, err
 	}
-	right, err := d[1].EvalValue()
-	if err != nil {
-		return nil, err
+	return evalDivide(ctx, operands[0], operands[1])
+}
+
+// evalDivide divides left by right.
+func evalDivide(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if diver, ok := left.(ContextEvalDivider); ok {
+		return diver.EvalDivideContext(ctx, right)
 	}
 	diver, ok := left.(EvalDivider)
 	if !ok {
-		return nil, errors.Errorf("cannot divide %v by %v", left, right)
+		return nil, typeMismatch("divide", left, right)
 	}
 	return diver.EvalDivide(right)
 }
@@ -241,3 +322,159 @@ func (d Div) Right() Expr {
 func (d Div) String() string {
 	return stringifyBinaryInfixHelper(d, "/")
 }
+
+// Mod binary expression
+type Mod binaryValue
+
+// EvalModder is the interface that can be implemented to customize how the
+// remainder of a division is computed.  Numbers that do not implement it are
+// handled by the Mod expression itself.
+type EvalModder interface {
+	EvalMod(v Value) (Value, error)
+}
+
+// ContextEvalModder is an EvalModder whose remainder depends on evaluation
+// options stored in the context.
+type ContextEvalModder interface {
+	EvalModder
+	EvalModContext(ctx context.Context, v Value) (Value, error)
+}
+
+// Copy the expression.
+func (m Mod) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(Mod(binaryValue(m).copy(transformations...)), transformations...)
+}
+
+// Eval the expression.
+func (m Mod) Eval() (interface{}, error) {
+	value, err := m.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (m Mod) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), m)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (m Mod) EvalValueContext(ctx context.Context) (Value, error) {
+	var operands [2]Value
+	err := evalValuesContext(ctx, m[:], operands[:])
+	if err != nil {
+		return nil, err
+	}
+	return evalMod(ctx, operands[0], operands[1])
+}
+
+// evalMod gets the remainder of dividing left by right.
+func evalMod(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if modder, ok := left.(ContextEvalModder); ok {
+		return modder.EvalModContext(ctx, right)
+	}
+	if modder, ok := left.(EvalModder); ok {
+		return modder.EvalMod(right)
+	}
+	l, lok := left.(Number)
+	r, rok := right.(Number)
+	if !lok || !rok {
+		return nil, typeMismatch("mod", left, right)
+	}
+	return numberModHelper(ctx, l, r)
+}
+
+// Left side of the binary expression.
+func (m Mod) Left() Expr {
+	return m[0]
+}
+
+// Right side of the binary expression.
+func (m Mod) Right() Expr {
+	return m[1]
+}
+
+// String represents the expression as a string.
+func (m Mod) String() string {
+	return stringifyBinaryInfixHelper(m, "%")
+}
+
+// Pow binary expression
+type Pow binaryValue
+
+// EvalPowerer is the interface that can be implemented to customize how a
+// value is raised to a power.  Numbers that do not implement it are handled
+// by the Pow expression itself.
+type EvalPowerer interface {
+	EvalPow(v Value) (Value, error)
+}
+
+// ContextEvalPowerer is an EvalPowerer whose result depends on evaluation
+// options stored in the context.
+type ContextEvalPowerer interface {
+	EvalPowerer
+	EvalPowContext(ctx context.Context, v Value) (Value, error)
+}
+
+// Copy the expression.
+func (p Pow) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(Pow(binaryValue(p).copy(transformations...)), transformations...)
+}
+
+// Eval the expression.
+func (p Pow) Eval() (interface{}, error) {
+	value, err := p.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (p Pow) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), p)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (p Pow) EvalValueContext(ctx context.Context) (Value, error) {
+	var operands [2]Value
+	err := evalValuesContext(ctx, p[:], operands[:])
+	if err != nil {
+		return nil, err
+	}
+	return evalPow(ctx, operands[0], operands[1])
+}
+
+// evalPow raises left to the power of right.
+func evalPow(ctx context.Context, left, right Value) (Value, error) {
+	left, right = unwrapDynamics(left, right)
+	if powerer, ok := left.(ContextEvalPowerer); ok {
+		return powerer.EvalPowContext(ctx, right)
+	}
+	if powerer, ok := left.(EvalPowerer); ok {
+		return powerer.EvalPow(right)
+	}
+	l, lok := left.(Number)
+	r, rok := right.(Number)
+	if !lok || !rok {
+		return nil, typeMismatch("raise", left, right)
+	}
+	return numberPowHelper(ctx, l, r)
+}
+
+// Left side of the binary expression.
+func (p Pow) Left() Expr {
+	return p[0]
+}
+
+// Right side of the binary expression.
+func (p Pow) Right() Expr {
+	return p[1]
+}
+
+// String represents the expression as a string.
+func (p Pow) String() string {
+	return stringifyBinaryInfixHelper(p, "**")
+}
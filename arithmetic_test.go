@@ -1,6 +1,7 @@
 package expr_test
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -105,3 +106,45 @@ func arithmeticTestHelper(t *testing.T, e expr.ValueExpr, resultChecker func(v e
 	}
 	t.Logf("%v -> %v", e, result)
 }
+
+func TestFloatArithmetic(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		expr.ValueExpr
+		expect expr.Value
+	}{
+		{expr.Add{expr.Float32(0.5), expr.Float32(0.25)}, expr.Float32(0.75)},
+		{expr.Add{expr.Float32(0.5), expr.Int(1)}, expr.Float64(1.5)},
+		{expr.Sub{expr.Float64(0.5), expr.Int(1)}, expr.Float64(-0.5)},
+		{expr.Mul{expr.Float64(0.5), expr.Float32(3)}, expr.Float64(1.5)},
+	}
+	for _, tc := range tcs {
+		actual, err := tc.EvalValue()
+		if err != nil {
+			t.Errorf("failed to evaluate %v: %v", tc.ValueExpr, err)
+			continue
+		}
+		if actual != tc.expect {
+			t.Errorf("%v -> %#v (expected %#v)", tc.ValueExpr, actual, tc.expect)
+		}
+	}
+	if v, err := expr.Float64(0.5).Eval(); err != nil || v != 0.5 {
+		t.Errorf("expected 0.5 but got %#v (err: %v)", v, err)
+	}
+}
+
+func TestArithmeticString(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		expr.Expr
+		expect string
+	}{
+		{expr.Add{expr.Int(3), expr.Int(1)}, "3 + 1"},
+		{expr.Sub{expr.Int(3), expr.Int(1)}, "3 - 1"},
+	}
+	for _, tc := range tcs {
+		if actual := tc.Expr.(fmt.Stringer).String(); actual != tc.expect {
+			t.Errorf("expected %q but got %q", tc.expect, actual)
+		}
+	}
+}
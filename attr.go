@@ -1,6 +1,8 @@
 package expr
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 )
 
@@ -12,10 +14,45 @@ import (
 // error.  If the value implements AttrGetter, GetAttrValue just uses the
 // value's GetAttr function to get the value.
 func GetAttrValue(value Value, name String) (Value, error) {
-	if ag, ok := value.(AttrGetter); ok {
-		return ag.GetAttr(name)
+	return getAttrValue(nil, value, name)
+}
+
+// getAttrValue gets an attribute from the given value after checking that
+// the Policy allows it.
+func getAttrValue(p *Policy, value Value, name String) (Value, error) {
+	switch v := value.(type) {
+	case Dynamic:
+		return v.getAttr(p, name)
+	case AttrGetter:
+		if err := p.checkMember(reflect.TypeOf(value), string(name)); err != nil {
+			return nil, err
+		}
+		return v.GetAttr(name)
+	}
+	return Dynamic(reflect.ValueOf(value)).getAttr(p, name)
+}
+
+// SetAttrValue sets an attribute of the given value to v.
+//
+// The value must implement AttrSetter or be a pointer to a struct with an
+// exported field called name whose type can hold v.
+func SetAttrValue(value Value, name String, v Value) error {
+	return setAttrValue(nil, value, name, v)
+}
+
+// setAttrValue sets an attribute of the given value after checking that the
+// Policy allows it.
+func setAttrValue(p *Policy, value Value, name String, v Value) error {
+	switch x := value.(type) {
+	case Dynamic:
+		return x.setAttr(p, name, v)
+	case AttrSetter:
+		if err := p.checkMember(reflect.TypeOf(value), string(name)); err != nil {
+			return err
+		}
+		return x.SetAttr(name, v)
 	}
-	return Dynamic(reflect.ValueOf(value)).GetAttr(name)
+	return Dynamic(reflect.ValueOf(value)).setAttr(p, name, v)
 }
 
 // Attr gets an attribute of a value (e.g. value.name).
@@ -56,9 +93,55 @@ func (a Attr) Eval() (interface{}, error) {
 
 // EvalValue evaluates the Attr expression into a Value
 func (a Attr) EvalValue() (Value, error) {
-	value, err := a.ValueExpr.EvalValue()
+	return EvalContext(context.Background(), a)
+}
+
+// EvalValueContext evaluates the Attr expression into a Value under ctx.
+func (a Attr) EvalValueContext(ctx context.Context) (Value, error) {
+	value, err := evalValueContext(ctx, a.ValueExpr)
 	if err != nil {
 		return nil, err
 	}
-	return GetAttrValue(value, a.Name)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	value, err = getAttrValue(PolicyFromContext(ctx), value, a.Name)
+	if ae, ok := err.(*AccessError); ok && ae.Path == "" {
+		ae.Path = a.accessPath()
+	}
+	return value, err
+}
+
+// Assign evaluates a's ValueExpr under ctx and sets its attribute to v.
+func (a Attr) Assign(ctx context.Context, v Value) error {
+	value, err := EvalContext(ctx, a.ValueExpr)
+	if err != nil {
+		return err
+	}
+	err = setAttrValue(PolicyFromContext(ctx), value, a.Name, v)
+	if ae, ok := err.(*AccessError); ok && ae.Path == "" {
+		ae.Path = a.accessPath()
+	}
+	return err
+}
+
+// accessPath renders the names of the attributes accessed by a.  The value
+// that the path starts from is represented by its type so that the values
+// protected by a Policy do not end up in error messages.
+func (a Attr) accessPath() string {
+	switch e := a.ValueExpr.(type) {
+	case Attr:
+		return e.accessPath() + "." + string(a.Name)
+	case Dynamic:
+		if v := reflect.Value(e); v.IsValid() {
+			return "(" + v.Type().String() + ")." + string(a.Name)
+		}
+	}
+	return fmt.Sprintf("(%T).%s", a.ValueExpr, a.Name)
+}
+
+// String represents the expression as a string.
+func (a Attr) String() string {
+	_, chained := a.ValueExpr.(Attr)
+	return StringifyExpr(a.ValueExpr, !chained) + "." + string(a.Name)
 }
@@ -1,6 +1,9 @@
 package expr_test
 
 import (
+	"context"
+	stderrors "errors"
+	"reflect"
 	"testing"
 
 	"github.com/skillian/logging"
@@ -115,3 +118,61 @@ func TestAttr(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAttr(t *testing.T) {
+	t.Parallel()
+	type account struct {
+		Owner   string
+		Balance int64
+		secret  string
+	}
+	acct := &account{Owner: "Sean"}
+	v := expr.ValueOf(acct)
+	if err := expr.SetAttrValue(v, "Balance", expr.Int(42)); err != nil {
+		t.Fatal(err)
+	}
+	if err := (expr.Attr{ValueExpr: v, Name: "Owner"}).Assign(context.Background(), expr.String("Ryan")); err != nil {
+		t.Fatal(err)
+	}
+	if acct.Balance != 42 || acct.Owner != "Ryan" {
+		t.Errorf("unexpected account after assignment: %+v", acct)
+	}
+	var ce *expr.ConversionError
+	if err := expr.SetAttrValue(v, "Balance", expr.String("lots")); !stderrors.As(err, &ce) {
+		t.Errorf("expected *ConversionError but got %v", err)
+	}
+	var ua *expr.UnknownAttributeError
+	if err := expr.SetAttrValue(v, "secret", expr.String("x")); !stderrors.As(err, &ua) {
+		t.Errorf("expected *UnknownAttributeError but got %v", err)
+	}
+	if err := expr.SetAttrValue(expr.ValueOf(*acct), "Balance", expr.Int(1)); err == nil {
+		t.Errorf("expected setting a field of a struct value to fail")
+	}
+	p := (&expr.Policy{}).DenyMember(reflect.TypeOf(account{}), "Balance")
+	ctx := expr.WithPolicy(context.Background(), p)
+	var ae *expr.AccessError
+	if err := (expr.Attr{ValueExpr: v, Name: "Balance"}).Assign(ctx, expr.Int(0)); !stderrors.As(err, &ae) {
+		t.Errorf("expected *AccessError but got %v", err)
+	}
+	if acct.Balance != 42 {
+		t.Errorf("denied assignment modified the account: %+v", acct)
+	}
+}
+
+type attrmethods struct{}
+
+func (attrmethods) Touch() {}
+
+func (attrmethods) Lookup() (string, bool) { return "", false }
+
+func (attrmethods) Parse() (int, error) { return 0, stderrors.New("cannot parse") }
+
+func TestAttrMethodResults(t *testing.T) {
+	t.Parallel()
+	v := expr.ValueOf(attrmethods{})
+	for _, name := range []expr.String{"Touch", "Lookup", "Parse"} {
+		if _, err := (expr.Attr{ValueExpr: v, Name: name}).EvalValue(); err == nil {
+			t.Errorf("expected getting %v to fail", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package expr
+
+import (
+	"context"
+	"math/big"
+)
+
+// BigInt wraps an arbitrary-precision integer.
+type BigInt big.Int
+
+type biginttype struct{}
+
+// BigIntType is the expression Type of a BigInt value.
+var BigIntType Type = biginttype{}
+
+// NewBigInt creates a new BigInt from a math/big.Int.
+func NewBigInt(i *big.Int) *BigInt {
+	return (*BigInt)(new(big.Int).Set(i))
+}
+
+// Zero creates a new zero-value BigInt.
+func (t biginttype) Zero() Value {
+	return (*BigInt)(new(big.Int))
+}
+
+// Var creates a new BigInt variable.
+func (t biginttype) Var() Var {
+	return &BigIntVar{value: (*BigInt)(new(big.Int))}
+}
+
+// Int gets the underlying *big.Int.  It must not be modified.
+func (b *BigInt) Int() *big.Int {
+	return (*big.Int)(b)
+}
+
+// Cmp implements Cmper.
+func (b *BigInt) Cmp(v Value) (int, error) {
+	if o, ok := v.(*BigInt); ok {
+		return b.Int().Cmp(o.Int()), nil
+	}
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(b, n)
+	}
+	return 0, notComparable(b, v, nil)
+}
+
+// Copy the expression.
+func (b *BigInt) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(NewBigInt(b.Int()), transformations...)
+}
+
+// Eval the expression.
+func (b *BigInt) Eval() (interface{}, error) {
+	return b.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a Value.
+func (b *BigInt) EvalValue() (Value, error) {
+	return b, nil
+}
+
+// Interface gets a copy of the BigInt as a *big.Int.
+func (b *BigInt) Interface() interface{} {
+	return new(big.Int).Set(b.Int())
+}
+
+// Type gets the BigInt value's Type: BigIntType.
+func (b *BigInt) Type() Type {
+	return BigIntType
+}
+
+// Rat stores b's value into r.
+func (b *BigInt) Rat(r *big.Rat) {
+	r.SetInt(b.Int())
+}
+
+// String represents b as a string.
+func (b *BigInt) String() string {
+	return b.Int().String()
+}
+
+// EvalAdd adds v to b.
+func (b *BigInt) EvalAdd(v Value) (Value, error) {
+	return bigIntArithmeticHelper(opAdd, b, v)
+}
+
+// EvalSubtract subtracts v from b.
+func (b *BigInt) EvalSubtract(v Value) (Value, error) {
+	return bigIntArithmeticHelper(opSub, b, v)
+}
+
+// EvalMultiply multiplies b by v.
+func (b *BigInt) EvalMultiply(v Value) (Value, error) {
+	return bigIntArithmeticHelper(opMul, b, v)
+}
+
+// EvalDivide divides b by v.
+func (b *BigInt) EvalDivide(v Value) (Value, error) {
+	return b.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides b by v under ctx.  The quotient is exact so it
+// is not necessarily an integer.
+func (b *BigInt) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(ctx, opQuo, b, v)
+	}
+	if n, ok := v.(Number); ok {
+		return numberDivideHelper(ctx, b, n)
+	}
+	return nil, typeMismatch("divide", b, v)
+}
+
+// EvalMod gets the remainder of dividing b by v.
+func (b *BigInt) EvalMod(v Value) (Value, error) {
+	return b.EvalModContext(context.Background(), v)
+}
+
+// EvalModContext gets the remainder of dividing b by v under ctx.
+func (b *BigInt) EvalModContext(ctx context.Context, v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return numberModHelper(ctx, b, n)
+	}
+	return nil, typeMismatch("mod", b, v)
+}
+
+// EvalPow raises b to the power of v.
+func (b *BigInt) EvalPow(v Value) (Value, error) {
+	return b.EvalPowContext(context.Background(), v)
+}
+
+// EvalPowContext raises b to the power of v under ctx.
+func (b *BigInt) EvalPowContext(ctx context.Context, v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return numberPowHelper(ctx, b, n)
+	}
+	return nil, typeMismatch("raise", b, v)
+}
+
+// BigIntVar is a variable that holds a BigInt value.
+type BigIntVar struct {
+	value *BigInt
+}
+
+// Copy copies the BigIntVar.
+func (b *BigIntVar) Copy(transformations ...Mapper) Expr {
+	copied := b.value.Copy(transformations...)
+	if bi, ok := copied.(*BigInt); ok {
+		return ApplyMappers(&BigIntVar{bi}, transformations...)
+	}
+	return copied
+}
+
+// Eval evaluates the BigIntVar.
+func (b *BigIntVar) Eval() (interface{}, error) {
+	if b.value == nil {
+		return nil, &NilVarError{}
+	}
+	return b.value.Eval()
+}
+
+// EvalValue evaluates the BigIntVar to its BigInt value.
+func (b *BigIntVar) EvalValue() (Value, error) {
+	if b.value == nil {
+		return nil, &NilVarError{}
+	}
+	return b.value, nil
+}
+
+// Value gets the BigInt value of the variable.
+func (b *BigIntVar) Value() Value {
+	return b.value
+}
+
+// SetValue sets b's value to v converted into a BigInt.
+func (b *BigIntVar) SetValue(v Value) error {
+	c, err := Convert(v, BigIntType)
+	if err != nil {
+		return err
+	}
+	b.value = NewBigInt(c.(*BigInt).Int())
+	return nil
+}
+
+// isInteger returns true if v is a fixed-width integer or a BigInt.
+func isInteger(v Value) bool {
+	if isFixedInt(v) {
+		return true
+	}
+	_, ok := v.(*BigInt)
+	return ok
+}
+
+// bigIntArithmeticHelper performs op on left and v.  If v is an integer, the
+// result is a BigInt and otherwise the operation is performed like any other
+// Number.
+func bigIntArithmeticHelper(op arithOp, left *BigInt, v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(context.Background(), op, left, v)
+	}
+	n, ok := v.(Number)
+	if !ok {
+		return nil, typeMismatch(op.name, left, v)
+	}
+	if !isInteger(v) {
+		return numberArithmeticHelper(op, left, n)
+	}
+	var r big.Rat
+	n.Rat(&r)
+	return (*BigInt)(op.int(new(big.Int), left.Int(), r.Num())), nil
+}
@@ -0,0 +1,145 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestBigInt(t *testing.T) {
+	t.Parallel()
+	product, err := expr.Mul{expr.Int64(math.MaxInt64), expr.Int64(math.MaxInt64)}.EvalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(math.MaxInt64))
+	b, ok := product.(*expr.Rational).Value().(*expr.BigInt)
+	if !ok || b.Int().Cmp(expect) != 0 {
+		t.Fatalf("MaxInt64 * MaxInt64 -> %v (type: %T)", product, product)
+	}
+	if b.String() != expect.String() {
+		t.Errorf("expected %v but got %v", expect, b)
+	}
+	sum, err := expr.Add{b, expr.Int(1)}.EvalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := sum.(*expr.BigInt); !ok || s.Int().Cmp(new(big.Int).Add(expect, big.NewInt(1))) != 0 {
+		t.Errorf("%v + 1 -> %v (type: %T)", b, sum, sum)
+	}
+	if cmp, err := expr.Cmp(b, expr.Uint64(math.MaxUint64)); err != nil || cmp != 1 {
+		t.Errorf("cmp(%v, MaxUint64) -> %d (err: %v)", b, cmp, err)
+	}
+	v := expr.BigIntType.Var()
+	if err := v.SetValue(expr.Int(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetValue((*expr.Rational)(big.NewRat(1, 2))); err == nil {
+		t.Errorf("expected error setting %v to 1/2", v)
+	}
+}
+
+func TestModPow(t *testing.T) {
+	t.Parallel()
+	twoTo100 := new(big.Int).Lsh(big.NewInt(1), 100)
+	tcs := []struct {
+		mode expr.IntMode
+		expr.ValueExpr
+		expect expr.Value
+		err    interface{}
+	}{
+		{expr.RationalInts, expr.Pow{expr.Int(2), expr.Int(100)}, expr.NewBigInt(twoTo100), nil},
+		{expr.RationalInts, expr.Pow{expr.Int(2), expr.Int(-2)}, expr.Float64(0.25), nil},
+		{expr.RationalInts, expr.Pow{(*expr.Rational)(big.NewRat(2, 3)), expr.Int(2)}, (*expr.Rational)(big.NewRat(4, 9)), nil},
+		{expr.RationalInts, expr.Pow{expr.Float64(4), expr.Float64(0.5)}, expr.Float64(2), nil},
+		{expr.RationalInts, expr.Pow{expr.Int(0), expr.Int(-1)}, nil, new(*expr.DivisionByZeroError)},
+		{expr.RationalInts, expr.Pow{expr.Int(4), (*expr.Rational)(big.NewRat(1, 3))}, nil, new(*expr.TypeMismatchError)},
+		{expr.RationalInts, expr.Mod{expr.Int(-7), expr.Int(3)}, expr.Int64(-1), nil},
+		{expr.RationalInts, expr.Mod{expr.NewBigInt(twoTo100), expr.Int(3)}, expr.Int64(1), nil},
+		{expr.RationalInts, expr.Mod{expr.NewBigInt(twoTo100), expr.Int(0)}, nil, new(*expr.DivisionByZeroError)},
+		{expr.RationalInts, expr.Mod{expr.Float64(7.5), expr.Int(2)}, expr.Float64(1.5), nil},
+		{expr.CheckedInts, expr.Mod{expr.Int8(-7), expr.Int8(3)}, expr.Int8(-1), nil},
+		{expr.CheckedInts, expr.Pow{expr.Int8(2), expr.Int8(6)}, expr.Int8(64), nil},
+		{expr.CheckedInts, expr.Pow{expr.Int8(2), expr.Int8(7)}, nil, new(*expr.OverflowError)},
+		{expr.CheckedInts, expr.Pow{expr.Int64(-3), expr.Int64(1 << 40)}, nil, new(*expr.OverflowError)},
+		{expr.SaturateInts, expr.Pow{expr.Int8(-2), expr.Int8(9)}, expr.Int8(math.MinInt8), nil},
+		{expr.WrapInts, expr.Pow{expr.Uint8(3), expr.Uint8(200)}, expr.Uint8(new(big.Int).Exp(big.NewInt(3), big.NewInt(200), big.NewInt(256)).Uint64()), nil},
+	}
+	for _, tc := range tcs {
+		ctx := expr.WithIntMode(context.Background(), tc.mode)
+		v, err := expr.EvalContext(ctx, tc.ValueExpr)
+		if tc.err != nil {
+			if !errors.As(err, tc.err) {
+				t.Errorf("%v: expected %T but got %v (result: %v)", tc.ValueExpr, tc.err, err, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tc.ValueExpr, err)
+			continue
+		}
+		if r, ok := v.(*expr.Rational); ok {
+			v = r.Value()
+		}
+		if cmp, err := expr.Cmp(v, tc.expect); err != nil || cmp != 0 {
+			t.Errorf("%v -> %v (type: %T) (expected %v (type: %T))",
+				tc.ValueExpr, v, v, tc.expect, tc.expect)
+		}
+		if isFixed(tc.expect) && v != tc.expect {
+			t.Errorf("%v -> %v (type: %T) (expected %v (type: %T))",
+				tc.ValueExpr, v, v, tc.expect, tc.expect)
+		}
+	}
+	limited := expr.WithLimits(context.Background(), expr.Limits{MaxRatBits: 256})
+	var le *expr.LimitError
+	if _, err := expr.EvalContext(limited, expr.Pow{expr.Int(3), expr.Int(1 << 40)}); !errors.As(err, &le) {
+		t.Errorf("expected *LimitError but got %v", err)
+	}
+}
+
+func TestPowLimit(t *testing.T) {
+	t.Parallel()
+	x := expr.Int(3)
+	tcs := []struct {
+		ctx context.Context
+		e   expr.Pow
+		max int
+	}{
+		{context.Background(), expr.Pow{&x, expr.Int(1 << 40)}, 1 << 20},
+		{context.Background(), expr.Pow{expr.Div{expr.Int(1), &x}, expr.Int(1 << 20)}, 1 << 20},
+		{expr.WithLimits(context.Background(), expr.Limits{MaxRatBits: 64}), expr.Pow{&x, expr.Int(64)}, 64},
+	}
+	for _, tc := range tcs {
+		_, err := expr.EvalContext(tc.ctx, tc.e)
+		var le *expr.LimitError
+		if !errors.As(err, &le) {
+			t.Errorf("%v: expected *LimitError but got %v", tc.e, err)
+			continue
+		}
+		if le.Kind != expr.RatLimit || le.Max != tc.max || !expr.SameExpr(le.Expr, tc.e) {
+			t.Errorf("%v: unexpected limit error: %#v", tc.e, le)
+		}
+	}
+	for _, e := range []expr.ValueExpr{
+		expr.Pow{expr.Int(1), expr.Int(1 << 62)},
+		expr.Pow{expr.Int(-1), expr.Int(1<<62 + 1)},
+		expr.Pow{expr.Int(0), expr.Int(1 << 62)},
+		expr.Pow{expr.Int(3), expr.Int(1000)},
+	} {
+		if _, err := expr.EvalContext(context.Background(), e); err != nil {
+			t.Errorf("%v: %v", e, err)
+		}
+	}
+}
+
+func isFixed(v expr.Value) bool {
+	switch v.(type) {
+	case expr.Int8, expr.Uint8:
+		return true
+	}
+	return false
+}
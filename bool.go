@@ -66,6 +66,12 @@ func (b Bool) EvalValue() (Value, error) {
 	return b, nil
 }
 
+// EvalBool evaluates the expression to a bool so that True and False can be
+// used as BoolExprs.
+func (b Bool) EvalBool() (bool, error) {
+	return bool(b), nil
+}
+
 // Type of the expression
 func (b Bool) Type() Type {
 	return BoolType
@@ -83,11 +89,20 @@ func (b *Bool) Value() Value {
 
 // SetValue sets the value of the expression.
 func (b *Bool) SetValue(v Value) error {
-	if v, ok := v.(Bool); ok {
-		*b = v
-		return nil
+	c, err := Convert(v, BoolType)
+	if err != nil {
+		return err
+	}
+	*b = c.(Bool)
+	return nil
+}
+
+// Equal checks if b is equal to another Bool.
+func (b Bool) Equal(v Value) (bool, error) {
+	if o, ok := v.(Bool); ok {
+		return b == o, nil
 	}
-	return TypeErrorFromExpectedAndActual(b, v)
+	return false, notComparable(b, v, nil)
 }
 
 // String gets the string representation of this bool.
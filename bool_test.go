@@ -1,6 +1,7 @@
 package expr_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/skillian/expr"
@@ -23,12 +24,56 @@ func TestEquality(t *testing.T) {
 		{expr.Ge{expr.Int(1), expr.Int(1)}, true},
 		{expr.Ge{expr.Int(-1), expr.Int(1)}, false},
 		{expr.Eq{expr.Int(1), &v}, true},
+		{expr.Eq{expr.True, expr.Bool(true)}, true},
+		{expr.Ne{expr.True, expr.False}, true},
+		{expr.Eq{expr.String("a"), expr.String("a")}, true},
+		{expr.Ne{expr.String("a"), expr.String("b")}, true},
+		{expr.Lt{expr.String("a"), expr.String("b")}, true},
+		{expr.Lt{expr.String("ab"), expr.String("b")}, true},
+		{expr.Gt{expr.String("a"), expr.String("B")}, true},
+		{expr.Ge{expr.String("a"), expr.String("a")}, true},
+		{expr.Lt{expr.String("a"), expr.String("")}, false},
+		{expr.Eq{expr.Complex128(6), expr.Int(6)}, true},
+		{expr.Eq{expr.Int(6), expr.Complex64(6 + 1i)}, false},
+		{expr.Eq{expr.ValueSet{expr.Int(1), expr.String("a")}, expr.ValueSet{expr.Int64(1), expr.String("a")}}, true},
+		{expr.Eq{expr.ValueSet{expr.Int(1)}, expr.ValueSet{expr.Int(1), expr.Int(2)}}, false},
+		{expr.Ne{expr.ValueSet{expr.True}, expr.ValueSet{expr.False}}, true},
 	}
 	for _, tc := range tcs {
 		testHelper(t, tc.Expr, tc.expect)
 	}
 }
 
+func TestEqualityWithoutOrdering(t *testing.T) {
+	t.Parallel()
+	type tagged struct {
+		Name string
+		Tags []string
+	}
+	a := expr.ValueOf(tagged{"a", []string{"x", "y"}})
+	b := expr.ValueOf(tagged{"a", []string{"x", "y"}})
+	c := expr.ValueOf(tagged{"a", []string{"x"}})
+	if eq, err := (expr.Eq{a, b}).EvalBool(); err != nil || !eq {
+		t.Errorf("%v == %v -> %v (err: %v)", a, b, eq, err)
+	}
+	if ne, err := (expr.Ne{a, c}).EvalBool(); err != nil || !ne {
+		t.Errorf("%v != %v -> %v (err: %v)", a, c, ne, err)
+	}
+	var nc *expr.NotComparableError
+	if _, err := (expr.Lt{a, b}).EvalBool(); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+	if _, err := expr.Cmp(expr.ValueOf([]int{1}), expr.ValueOf([]int{1})); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+	if _, err := (expr.Eq{expr.True, expr.String("true")}).EvalBool(); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+	if _, err := (expr.Lt{expr.String("1"), expr.Int(2)}).EvalBool(); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+}
+
 func testHelper(t *testing.T, e expr.Expr, expected interface{}) {
 	t.Helper()
 	result, err := e.Eval()
@@ -42,3 +87,20 @@ func testHelper(t *testing.T, e expr.Expr, expected interface{}) {
 	}
 	t.Logf("%v -> %v", e, result)
 }
+
+func TestMultaryCopy(t *testing.T) {
+	t.Parallel()
+	two := func(e expr.Expr) expr.Expr {
+		if e == expr.Expr(expr.Int(1)) {
+			return expr.Int(2)
+		}
+		return e
+	}
+	eq := expr.Eq{expr.Int(1), expr.Int(2)}
+	for _, e := range []expr.BoolExpr{expr.All{eq, expr.True}, expr.Any{eq, expr.False}} {
+		copied := e.Copy(two).(expr.BoolExpr)
+		if ok, err := copied.EvalBool(); err != nil || !ok {
+			t.Errorf("expected %v to be true (err: %v)", copied, err)
+		}
+	}
+}
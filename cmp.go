@@ -1,6 +1,9 @@
 package expr
 
-import "github.com/skillian/errors"
+import (
+	"context"
+	"reflect"
+)
 
 // Cmper implementers can compare themselves with other values.
 type Cmper interface {
@@ -8,6 +11,14 @@ type Cmper interface {
 	Cmp(v Value) (int, error)
 }
 
+// Equaler implementers can check if they're equal to other values.  Eq and
+// Ne use Equaler before falling back to Cmp so that values can be checked for
+// equality even if they cannot be ordered.
+type Equaler interface {
+	Value
+	Equal(v Value) (bool, error)
+}
+
 // Cmp compares the left expression to the right according the the following
 // rules:
 //
@@ -20,33 +31,143 @@ type Cmper interface {
 //
 // An error is returned if the two expressions cannot be compared.
 func Cmp(left, right Expr) (result int, err error) {
+	return cmpContext(context.Background(), left, right)
+}
+
+// cmpContext evaluates left and right under ctx and compares their values.
+func cmpContext(ctx context.Context, left, right Expr) (result int, err error) {
 	var comparands [2]Value
-	err = SimplifyIntoValues([]Expr{left, right}, comparands[:])
-	if err != nil {
-		return 0, err
+	for i, e := range [2]Expr{left, right} {
+		comparands[i], err = evalExprContext(ctx, e)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return cmpValues(PolicyFromContext(ctx), comparands[0], comparands[1])
+}
+
+// equalContext evaluates left and right under ctx and checks if their values
+// are equal.
+func equalContext(ctx context.Context, left, right Expr) (equal bool, err error) {
+	var comparands [2]Value
+	for i, e := range [2]Expr{left, right} {
+		comparands[i], err = evalExprContext(ctx, e)
+		if err != nil {
+			return false, err
+		}
 	}
-	if cmper, ok := comparands[0].(Cmper); ok {
-		result, err = cmper.Cmp(comparands[1])
+	return equalValues(PolicyFromContext(ctx), comparands[0], comparands[1])
+}
+
+// policyCmper is implemented by Cmpers that call methods that a Policy can
+// deny.
+type policyCmper interface {
+	cmp(p *Policy, v Value) (int, error)
+}
+
+// policyEqualer is implemented by Equalers that call methods that a Policy
+// can deny.
+type policyEqualer interface {
+	equal(p *Policy, v Value) (bool, error)
+}
+
+// cmpWith compares c to v under the Policy p.
+func cmpWith(p *Policy, c Cmper, v Value) (int, error) {
+	if pc, ok := c.(policyCmper); ok {
+		return pc.cmp(p, v)
+	}
+	return c.Cmp(v)
+}
+
+// equalWith checks if e is equal to v under the Policy p.
+func equalWith(p *Policy, e Equaler, v Value) (bool, error) {
+	if pe, ok := e.(policyEqualer); ok {
+		return pe.equal(p, v)
+	}
+	return e.Equal(v)
+}
+
+// EqualValues checks if the left value is equal to the right value.  If
+// neither value is an Equaler, the values are compared with CmpValues.
+func EqualValues(left, right Value) (equal bool, err error) {
+	return equalValues(nil, left, right)
+}
+
+// equalValues checks if left is equal to right under the Policy p.
+func equalValues(p *Policy, left, right Value) (equal bool, err error) {
+	left, right = unwrapDynamics(left, right)
+	if equaler, ok := left.(Equaler); ok {
+		if equal, err = equalWith(p, equaler, right); err == nil {
+			return equal, nil
+		}
+	}
+	if equaler, ok := right.(Equaler); ok {
+		if equal, err2 := equalWith(p, equaler, left); err2 == nil {
+			return equal, nil
+		}
+	}
+	result, err2 := cmpValues(p, left, right)
+	if err2 != nil {
+		if err != nil {
+			return false, notComparable(left, right, err)
+		}
+		return false, err2
+	}
+	return result == 0, nil
+}
+
+// CmpValues compares the left value to the right value according to the same
+// rules as Cmp.
+func CmpValues(left, right Value) (result int, err error) {
+	return cmpValues(nil, left, right)
+}
+
+// cmpValues compares left to right under the Policy p.
+func cmpValues(p *Policy, left, right Value) (result int, err error) {
+	left, right = unwrapDynamics(left, right)
+	if cmper, ok := left.(Cmper); ok {
+		result, err = cmpWith(p, cmper, right)
 		if err == nil {
 			return result, nil
 		}
 	}
-	if cmper, ok := comparands[1].(Cmper); ok {
-		inverted, err2 := cmper.Cmp(comparands[0])
+	if cmper, ok := right.(Cmper); ok {
+		inverted, err2 := cmpWith(p, cmper, left)
 		if err2 == nil {
 			if err != nil {
 				logger.Warn(
 					"comparing %v to %v succeeded but comparing %v to %v failed: %v",
-					comparands[1], comparands[0],
-					comparands[0], comparands[1], err)
+					right, left, left, right, err)
 			}
 			return -inverted, nil
 		}
-		// preserve err to see why we failed over to comparing right to left:
-		return 0, errors.CreateError(err2, nil, err, 0)
+		// prefer err to see why we failed over to comparing right to left:
+		if err == nil {
+			err = err2
+		}
+		if nc, ok := err.(*NotComparableError); ok {
+			return 0, nc
+		}
+		return 0, notComparable(left, right, err)
 	}
-	if left == right {
+	if identical(left, right) {
 		return 0, nil
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", left, right)
+	if nc, ok := err.(*NotComparableError); ok {
+		return 0, nc
+	}
+	return 0, notComparable(left, right, err)
+}
+
+// identical checks if left and right are the same value without panicking
+// if they hold values that Go cannot compare, like slices.
+func identical(left, right Value) bool {
+	lv, rv := reflect.ValueOf(left), reflect.ValueOf(right)
+	if !lv.IsValid() || !rv.IsValid() {
+		return lv.IsValid() == rv.IsValid()
+	}
+	if lv.Type() != rv.Type() || !lv.Comparable() {
+		return false
+	}
+	return left == right
 }
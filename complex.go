@@ -0,0 +1,236 @@
+package expr
+
+import (
+	"context"
+)
+
+// Complex64 wraps a Go complex64 value.
+type Complex64 complex64
+
+type complex64type struct{}
+
+// Complex64Type is the expr.Type of Complex64 values.
+var Complex64Type Type = complex64type{}
+
+func (t complex64type) Zero() Value {
+	return Complex64(0)
+}
+
+func (t complex64type) Var() Var {
+	return new(Complex64)
+}
+
+// Copy the expression.
+func (c Complex64) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(c, transformations...)
+}
+
+// Eval the expression.
+func (c Complex64) Eval() (interface{}, error) {
+	return c.Interface(), nil
+}
+
+// Interface of the expression.
+func (c Complex64) Interface() interface{} {
+	return complex64(c)
+}
+
+// EvalValue evaluates the expression to a value.
+func (c Complex64) EvalValue() (Value, error) {
+	return c, nil
+}
+
+// EvalAdd adds v to c.
+func (c Complex64) EvalAdd(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opAdd, c, v)
+}
+
+// EvalSubtract subtracts v from c.
+func (c Complex64) EvalSubtract(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opSub, c, v)
+}
+
+// EvalMultiply multiplies c by v.
+func (c Complex64) EvalMultiply(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opMul, c, v)
+}
+
+// EvalDivide divides c by v.
+func (c Complex64) EvalDivide(v Value) (Value, error) {
+	return c.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides c by v under ctx.
+func (c Complex64) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return complexArithmeticHelper(ctx, opQuo, c, v)
+}
+
+// Equal checks if c is equal to another complex number or Number.
+func (c Complex64) Equal(v Value) (bool, error) {
+	return complexEqualHelper(c, v)
+}
+
+// Type gets the expression type of c: Complex64Type.
+func (c Complex64) Type() Type {
+	return Complex64Type
+}
+
+// Value of the expression.
+func (c *Complex64) Value() Value {
+	return *c
+}
+
+// SetValue of the expression.
+func (c *Complex64) SetValue(v Value) error {
+	x, err := Convert(v, Complex64Type)
+	if err != nil {
+		return err
+	}
+	*c = x.(Complex64)
+	return nil
+}
+
+// Complex128 wraps a Go complex128 value.
+type Complex128 complex128
+
+type complex128type struct{}
+
+// Complex128Type is the expr.Type of Complex128 values.
+var Complex128Type Type = complex128type{}
+
+func (t complex128type) Zero() Value {
+	return Complex128(0)
+}
+
+func (t complex128type) Var() Var {
+	return new(Complex128)
+}
+
+// Copy the expression.
+func (c Complex128) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(c, transformations...)
+}
+
+// Eval the expression.
+func (c Complex128) Eval() (interface{}, error) {
+	return c.Interface(), nil
+}
+
+// Interface of the expression.
+func (c Complex128) Interface() interface{} {
+	return complex128(c)
+}
+
+// EvalValue evaluates the expression to a value.
+func (c Complex128) EvalValue() (Value, error) {
+	return c, nil
+}
+
+// EvalAdd adds v to c.
+func (c Complex128) EvalAdd(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opAdd, c, v)
+}
+
+// EvalSubtract subtracts v from c.
+func (c Complex128) EvalSubtract(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opSub, c, v)
+}
+
+// EvalMultiply multiplies c by v.
+func (c Complex128) EvalMultiply(v Value) (Value, error) {
+	return complexArithmeticHelper(context.Background(), opMul, c, v)
+}
+
+// EvalDivide divides c by v.
+func (c Complex128) EvalDivide(v Value) (Value, error) {
+	return c.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides c by v under ctx.
+func (c Complex128) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return complexArithmeticHelper(ctx, opQuo, c, v)
+}
+
+// Equal checks if c is equal to another complex number or Number.
+func (c Complex128) Equal(v Value) (bool, error) {
+	return complexEqualHelper(c, v)
+}
+
+// Type gets the expression type of c: Complex128Type.
+func (c Complex128) Type() Type {
+	return Complex128Type
+}
+
+// Value of the expression.
+func (c *Complex128) Value() Value {
+	return *c
+}
+
+// SetValue of the expression.
+func (c *Complex128) SetValue(v Value) error {
+	x, err := Convert(v, Complex128Type)
+	if err != nil {
+		return err
+	}
+	*c = x.(Complex128)
+	return nil
+}
+
+// complexEqualHelper checks if the complex number c is equal to v.
+func complexEqualHelper(c, v Value) (bool, error) {
+	x, _ := complexOf(c)
+	y, ok := complexOf(v)
+	if !ok {
+		return false, notComparable(c, v, nil)
+	}
+	return x == y, nil
+}
+
+// isComplex returns true if v is a complex number.
+func isComplex(v Value) bool {
+	switch v.(type) {
+	case Complex64, Complex128:
+		return true
+	}
+	return false
+}
+
+// complexOf gets v as a complex128 if it is a complex number or a Number.
+func complexOf(v Value) (complex128, bool) {
+	switch v := v.(type) {
+	case Complex64:
+		return complex128(v), true
+	case Complex128:
+		return complex128(v), true
+	case Number:
+		return complex(floatOf(v), 0), true
+	}
+	return 0, false
+}
+
+// complexArithmeticHelper performs op on left and right as complex numbers.
+// The result is a Complex64 if both operands are Complex64 or Float32 values
+// and a Complex128 otherwise.
+func complexArithmeticHelper(ctx context.Context, op arithOp, left, right Value) (Value, error) {
+	x, lok := complexOf(left)
+	y, rok := complexOf(right)
+	if !lok || !rok {
+		return nil, typeMismatch(op.name, left, right)
+	}
+	if op.divides && y == 0 && DivisionModeFromContext(ctx) != DivideByZeroIEEE {
+		return nil, divisionByZero(left, right)
+	}
+	result := op.complex(x, y)
+	if isComplex64Compatible(left) && isComplex64Compatible(right) {
+		return Complex64(complex64(result)), nil
+	}
+	return Complex128(result), nil
+}
+
+func isComplex64Compatible(v Value) bool {
+	switch v.(type) {
+	case Complex64, Float32:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/skillian/errors"
+)
+
+// ContextValueExpr is a ValueExpr that can be evaluated under a
+// context.Context so that its evaluation can be cancelled or limited.
+type ContextValueExpr interface {
+	ValueExpr
+	EvalValueContext(ctx context.Context) (Value, error)
+}
+
+// ContextBoolExpr is a BoolExpr that can be evaluated under a
+// context.Context.
+type ContextBoolExpr interface {
+	BoolExpr
+	EvalBoolContext(ctx context.Context) (bool, error)
+}
+
+// Limits are the budgets of a context-aware evaluation.  A zero field means
+// that the budget is unlimited.
+type Limits struct {
+	// MaxNodes is the maximum number of expression nodes that can be
+	// visited during an evaluation.
+	MaxNodes int
+
+	// MaxDepth is the maximum depth of nested expressions that can be
+	// evaluated.
+	MaxDepth int
+
+	// MaxRatBits is the maximum number of bits that the numerator and
+	// denominator of a rational result can have together.  Even when it
+	// is zero, the results of Pow are limited to about a million bits.
+	MaxRatBits int
+
+	// MaxClauses is the maximum number of terms that satisfiability
+	// checks can expand an expression into.
+	MaxClauses int
+}
+
+// LimitKind identifies which of the Limits was exceeded.
+type LimitKind int
+
+const (
+	// NodeLimit is the kind of LimitError returned when Limits.MaxNodes
+	// is exceeded.
+	NodeLimit LimitKind = iota
+
+	// DepthLimit is the kind of LimitError returned when Limits.MaxDepth
+	// is exceeded.
+	DepthLimit
+
+	// RatLimit is the kind of LimitError returned when Limits.MaxRatBits
+	// is exceeded.
+	RatLimit
+
+	// ClauseLimit is the kind of LimitError returned when the conversion
+	// of an expression into a normal form would have more clauses than
+	// allowed.
+	ClauseLimit
+
+	// TermLimit is the kind of LimitError returned when the expansion of
+	// an expression would have more terms than allowed.
+	TermLimit
+)
+
+func (k LimitKind) String() string {
+	switch k {
+	case NodeLimit:
+		return "node"
+	case DepthLimit:
+		return "depth"
+	case RatLimit:
+		return "rational size"
+	case ClauseLimit:
+		return "clause"
+	case TermLimit:
+		return "term"
+	}
+	return fmt.Sprintf("LimitKind(%d)", int(k))
+}
+
+// LimitError is returned by context-aware evaluations that exceed one of
+// their Limits.
+type LimitError struct {
+	// Kind of limit that was exceeded.
+	Kind LimitKind
+
+	// Max is the configured value of the limit.
+	Max int
+
+	// Expr is the expression whose evaluation exceeded the limit.
+	Expr Expr
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf(
+		"%v limit of %d exceeded while evaluating %v",
+		e.Kind, e.Max, e.Expr)
+}
+
+type limitsKey struct{}
+
+// WithLimits returns a copy of ctx that applies the given Limits to
+// evaluations performed with EvalContext and EvalBoolContext.
+func WithLimits(ctx context.Context, limits Limits) context.Context {
+	return context.WithValue(ctx, limitsKey{}, limits)
+}
+
+// LimitsFromContext gets the Limits associated with the context.
+func LimitsFromContext(ctx context.Context) (Limits, bool) {
+	limits, ok := ctx.Value(limitsKey{}).(Limits)
+	return limits, ok
+}
+
+// evalState keeps track of a single evaluation.
+type evalState struct {
+	Limits
+
+	// nodes is the number of nodes visited so far.
+	nodes int
+
+	// path holds the expressions from the root of the evaluation to the
+	// expression currently being evaluated.
+	path []Expr
+
+	// trace records the evaluation if it is being explained.
+	trace *tracer
+}
+
+type evalStateKey struct{}
+
+// withEvalState adds a new evalState to the context unless the context
+// already has one.
+func withEvalState(ctx context.Context) context.Context {
+	if evalStateFromContext(ctx) != nil {
+		return ctx
+	}
+	st := new(evalState)
+	st.Limits, _ = LimitsFromContext(ctx)
+	return context.WithValue(ctx, evalStateKey{}, st)
+}
+
+func evalStateFromContext(ctx context.Context) *evalState {
+	st, _ := ctx.Value(evalStateKey{}).(*evalState)
+	return st
+}
+
+// enter checks that e may be evaluated and pushes it onto the path.
+func (st *evalState) enter(e Expr) error {
+	if st.MaxNodes > 0 && st.nodes >= st.MaxNodes {
+		return &LimitError{Kind: NodeLimit, Max: st.MaxNodes, Expr: e}
+	}
+	if st.MaxDepth > 0 && len(st.path) >= st.MaxDepth {
+		return &LimitError{Kind: DepthLimit, Max: st.MaxDepth, Expr: e}
+	}
+	st.nodes++
+	st.path = append(st.path, e)
+	if st.trace != nil {
+		st.trace.enter(e)
+	}
+	return nil
+}
+
+// currentPath gets the path from the root of the evaluation to the current
+// expression.  It is safe to call on a nil *evalState.
+func (st *evalState) currentPath() []Expr {
+	if st == nil {
+		return nil
+	}
+	return st.path
+}
+
+// current gets the expression currently being evaluated or def when there
+// is none.  It is safe to call on a nil *evalState.
+func (st *evalState) current(def Expr) Expr {
+	if path := st.currentPath(); len(path) > 0 {
+		return path[len(path)-1]
+	}
+	return def
+}
+
+// leave pops the current expression off of the path.
+func (st *evalState) leave() {
+	st.path = st.path[:len(st.path)-1]
+}
+
+// checkValue checks the value produced by e against the limits.
+func (st *evalState) checkValue(e Expr, v Value) error {
+	if st.MaxRatBits <= 0 {
+		return nil
+	}
+	if r, ok := v.(*Rational); ok {
+		br := (*big.Rat)(r)
+		if br.Num().BitLen()+br.Denom().BitLen() > st.MaxRatBits {
+			return &LimitError{Kind: RatLimit, Max: st.MaxRatBits, Expr: e}
+		}
+	}
+	if b, ok := v.(*BigInt); ok && b.Int().BitLen() > st.MaxRatBits {
+		return &LimitError{Kind: RatLimit, Max: st.MaxRatBits, Expr: e}
+	}
+	return nil
+}
+
+// EvalContext evaluates e into a Value.  The evaluation is aborted with the
+// context's error when the context is done or with a *LimitError when one of
+// the Limits associated with the context is exceeded.
+func EvalContext(ctx context.Context, e ValueExpr) (Value, error) {
+	return evalValueContext(withEvalState(ctx), e)
+}
+
+// EvalBoolContext evaluates e into a bool under the same rules as
+// EvalContext.
+func EvalBoolContext(ctx context.Context, e BoolExpr) (bool, error) {
+	return evalBoolContext(withEvalState(ctx), e)
+}
+
+// evalValueContext evaluates e as a node of the evaluation tracked by ctx.
+func evalValueContext(ctx context.Context, e ValueExpr) (v Value, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	st := evalStateFromContext(ctx)
+	if st != nil {
+		if err = st.enter(e); err != nil {
+			return nil, err
+		}
+		defer st.leave()
+		if st.trace != nil {
+			defer func() { st.trace.leave(v, err) }()
+		}
+	}
+	switch e := e.(type) {
+	case ContextValueExpr:
+		v, err = e.EvalValueContext(ctx)
+	case ContextBoolExpr:
+		var b bool
+		b, err = e.EvalBoolContext(ctx)
+		v = Bool(b)
+	default:
+		v, err = e.EvalValue()
+	}
+	if err != nil {
+		annotate(err, e, st.currentPath())
+		return nil, err
+	}
+	if st != nil {
+		if err = st.checkValue(e, v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// evalBoolContext evaluates e as a node of the evaluation tracked by ctx.
+func evalBoolContext(ctx context.Context, e BoolExpr) (b bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return false, err
+	}
+	st := evalStateFromContext(ctx)
+	if st != nil {
+		if err = st.enter(e); err != nil {
+			return false, err
+		}
+		defer st.leave()
+		if st.trace != nil {
+			defer func() { st.trace.leave(Bool(b), err) }()
+		}
+	}
+	if ce, ok := e.(ContextBoolExpr); ok {
+		b, err = ce.EvalBoolContext(ctx)
+	} else {
+		b, err = e.EvalBool()
+	}
+	if err != nil {
+		annotate(err, e, st.currentPath())
+	}
+	return b, err
+}
+
+// evalExprContext evaluates an Expr operand that is expected to be a
+// ValueExpr.
+func evalExprContext(ctx context.Context, e Expr) (Value, error) {
+	ve, ok := e.(ValueExpr)
+	if !ok {
+		return nil, errors.Errorf(
+			"expression must be a ValueExpr in order to be evaluated "+
+				"into a value (was %T)", e)
+	}
+	return evalValueContext(ctx, ve)
+}
+
+// evalValuesContext evaluates each of the expressions into values.
+func evalValuesContext(ctx context.Context, exprs []ValueExpr, values []Value) (err error) {
+	for i, e := range exprs {
+		values[i], err = evalValueContext(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
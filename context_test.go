@@ -0,0 +1,81 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestEvalContext(t *testing.T) {
+	t.Parallel()
+	nested := expr.Add{expr.Add{expr.Int(1), expr.Int(2)}, expr.Int(3)}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tcs := []struct {
+		name string
+		ctx  context.Context
+		expr.ValueExpr
+		limit  expr.LimitKind
+		hasErr bool
+	}{
+		{"nested", context.Background(), nested, 0, false},
+		{"cancelled", cancelled, nested, 0, true},
+		{"max nodes", expr.WithLimits(context.Background(), expr.Limits{MaxNodes: 3}), nested, expr.NodeLimit, true},
+		{"max depth", expr.WithLimits(context.Background(), expr.Limits{MaxDepth: 2}), nested, expr.DepthLimit, true},
+		{"max rat bits", expr.WithLimits(context.Background(), expr.Limits{MaxRatBits: 8}), expr.Div{expr.Int(1), expr.Int(1000)}, expr.RatLimit, true},
+		{"within limits", expr.WithLimits(context.Background(), expr.Limits{MaxNodes: 5, MaxDepth: 3}), nested, 0, false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := expr.EvalContext(tc.ctx, tc.ValueExpr)
+			if !tc.hasErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cmp, err := expr.Cmp(value, expr.Int(6)); err != nil || cmp != 0 {
+					t.Errorf("%v -> %v (expected 6)", tc.ValueExpr, value)
+				}
+				return
+			}
+			if tc.ctx == cancelled {
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("expected %v but got %v", context.Canceled, err)
+				}
+				return
+			}
+			var le *expr.LimitError
+			if !errors.As(err, &le) {
+				t.Fatalf("expected *LimitError but got %v", err)
+			}
+			if le.Kind != tc.limit {
+				t.Errorf("expected %v limit but got %v", tc.limit, le.Kind)
+			}
+		})
+	}
+}
+
+func TestEvalBoolContext(t *testing.T) {
+	t.Parallel()
+	e := expr.All{
+		expr.Eq{expr.Int(1), expr.Int(1)},
+		expr.Any{
+			expr.Gt{expr.Int(1), expr.Int(2)},
+			expr.Not{expr.Eq{expr.Int(1), expr.Int(2)}},
+		},
+	}
+	ok, err := expr.EvalBoolContext(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("%v evaluated to false", e)
+	}
+	_, err = expr.EvalBoolContext(
+		expr.WithLimits(context.Background(), expr.Limits{MaxNodes: 4}), e)
+	var le *expr.LimitError
+	if !errors.As(err, &le) || le.Kind != expr.NodeLimit {
+		t.Errorf("expected node limit error but got %v", err)
+	}
+}
@@ -0,0 +1,514 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Conversion classifies how values of one Type can be converted into
+// another Type.
+type Conversion int
+
+const (
+	// NoConversion means that values cannot be converted.
+	NoConversion Conversion = iota
+
+	// ExplicitConversion means that values can only be converted with a
+	// Cast because the conversion changes their representation, for
+	// example by parsing a String into a number.
+	ExplicitConversion
+
+	// LossyConversion means that some values cannot be represented exactly
+	// by the target Type.  Convert only converts values that can be; Cast
+	// truncates fractions towards zero, wraps integers that are out of
+	// range like Go does and rounds everything else.
+	LossyConversion
+
+	// LosslessConversion means that every value can be represented exactly
+	// by the target Type.
+	LosslessConversion
+)
+
+func (c Conversion) String() string {
+	switch c {
+	case NoConversion:
+		return "none"
+	case ExplicitConversion:
+		return "explicit"
+	case LossyConversion:
+		return "lossy"
+	case LosslessConversion:
+		return "lossless"
+	}
+	return fmt.Sprintf("Conversion(%d)", int(c))
+}
+
+// typeClass groups Types whose values are converted the same way.
+type typeClass int
+
+const (
+	otherClass typeClass = iota
+	intClass
+	bigIntClass
+	rationalClass
+	decimalClass
+	floatClass
+	complexClass
+	boolClass
+	stringClass
+	timeClass
+	durationClass
+)
+
+func classOf(t Type) typeClass {
+	switch t.(type) {
+	case inttype, int8type, int16type, int32type, int64type,
+		uinttype, uint8type, uint16type, uint32type, uint64type:
+		return intClass
+	case biginttype:
+		return bigIntClass
+	case rationaltype:
+		return rationalClass
+	case decimaltype:
+		return decimalClass
+	case float32type, float64type:
+		return floatClass
+	case complex64type, complex128type:
+		return complexClass
+	case booltype:
+		return boolClass
+	case stringtype:
+		return stringClass
+	case timetype:
+		return timeClass
+	case durationtype:
+		return durationClass
+	}
+	return otherClass
+}
+
+// conversions is the table of conversions between different Types by their
+// classes.  Lossy conversions between numeric Types are lossless when the
+// target Type is wide enough (see widens).  Conversions that aren't in the
+// table are not possible.
+var conversions = map[[2]typeClass]Conversion{
+	{intClass, intClass}:      LossyConversion,
+	{intClass, bigIntClass}:   LosslessConversion,
+	{intClass, rationalClass}: LosslessConversion,
+	{intClass, decimalClass}:  LosslessConversion,
+	{intClass, floatClass}:    LossyConversion,
+	{intClass, complexClass}:  LossyConversion,
+	{intClass, stringClass}:   ExplicitConversion,
+	{intClass, durationClass}: ExplicitConversion,
+
+	{bigIntClass, intClass}:      LossyConversion,
+	{bigIntClass, rationalClass}: LosslessConversion,
+	{bigIntClass, decimalClass}:  LosslessConversion,
+	{bigIntClass, floatClass}:    LossyConversion,
+	{bigIntClass, complexClass}:  LossyConversion,
+	{bigIntClass, stringClass}:   ExplicitConversion,
+
+	{rationalClass, intClass}:     LossyConversion,
+	{rationalClass, bigIntClass}:  LossyConversion,
+	{rationalClass, decimalClass}: LossyConversion,
+	{rationalClass, floatClass}:   LossyConversion,
+	{rationalClass, complexClass}: LossyConversion,
+	{rationalClass, stringClass}:  ExplicitConversion,
+
+	{decimalClass, intClass}:      LossyConversion,
+	{decimalClass, bigIntClass}:   LossyConversion,
+	{decimalClass, rationalClass}: LosslessConversion,
+	{decimalClass, decimalClass}:  LossyConversion,
+	{decimalClass, floatClass}:    LossyConversion,
+	{decimalClass, complexClass}:  LossyConversion,
+	{decimalClass, stringClass}:   ExplicitConversion,
+
+	{floatClass, intClass}:      LossyConversion,
+	{floatClass, bigIntClass}:   LossyConversion,
+	{floatClass, rationalClass}: LossyConversion,
+	{floatClass, decimalClass}:  LossyConversion,
+	{floatClass, floatClass}:    LossyConversion,
+	{floatClass, complexClass}:  LossyConversion,
+	{floatClass, stringClass}:   ExplicitConversion,
+
+	{complexClass, intClass}:      LossyConversion,
+	{complexClass, bigIntClass}:   LossyConversion,
+	{complexClass, rationalClass}: LossyConversion,
+	{complexClass, decimalClass}:  LossyConversion,
+	{complexClass, floatClass}:    LossyConversion,
+	{complexClass, complexClass}:  LossyConversion,
+	{complexClass, stringClass}:   ExplicitConversion,
+
+	{boolClass, stringClass}:     ExplicitConversion,
+	{timeClass, stringClass}:     ExplicitConversion,
+	{durationClass, stringClass}: ExplicitConversion,
+	{durationClass, intClass}:    ExplicitConversion,
+
+	{stringClass, intClass}:      ExplicitConversion,
+	{stringClass, bigIntClass}:   ExplicitConversion,
+	{stringClass, rationalClass}: ExplicitConversion,
+	{stringClass, decimalClass}:  ExplicitConversion,
+	{stringClass, floatClass}:    ExplicitConversion,
+	{stringClass, complexClass}:  ExplicitConversion,
+	{stringClass, boolClass}:     ExplicitConversion,
+	{stringClass, timeClass}:     ExplicitConversion,
+	{stringClass, durationClass}: ExplicitConversion,
+}
+
+// sameType returns true if a and b are the same Type.
+func sameType(a, b Type) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if s, ok := a.(settype); ok {
+		s2 := b.(settype)
+		if len(s.types) != len(s2.types) {
+			return false
+		}
+		for i, t := range s.types {
+			if !sameType(t, s2.types[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return !reflect.TypeOf(a).Comparable() || a == b
+}
+
+// ConversionOf classifies the conversion of values of the from Type into the
+// to Type.
+func ConversionOf(from, to Type) Conversion {
+	if sameType(from, to) {
+		return LosslessConversion
+	}
+	c := conversions[[2]typeClass{classOf(from), classOf(to)}]
+	if c == LossyConversion && widens(from, to) {
+		return LosslessConversion
+	}
+	if dt, ok := from.(dynamictype); ok && classOf(to) == stringClass && isStringer(dt.Type) {
+		return ExplicitConversion
+	}
+	return c
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// isStringer returns true if values of type t have a String method.
+func isStringer(t reflect.Type) bool {
+	return t.Implements(stringerType) || reflect.PointerTo(t).Implements(stringerType)
+}
+
+// mantissaBits gets the number of bits of the mantissa of a float or complex
+// Type's components.
+func mantissaBits(t Type) uint {
+	switch t.(type) {
+	case float32type, complex64type:
+		return 24
+	}
+	return 53
+}
+
+// widens returns true if every value of the numeric Type from can be
+// represented exactly by the numeric Type to.
+func widens(from, to Type) bool {
+	switch classOf(from) {
+	case intClass:
+		fk, _ := intKindOf(from.Zero())
+		switch classOf(to) {
+		case intClass:
+			tk, _ := intKindOf(to.Zero())
+			if fk.signed && !tk.signed {
+				return false
+			}
+			if fk.signed == tk.signed {
+				return fk.bits <= tk.bits
+			}
+			return fk.bits < tk.bits
+		case floatClass, complexClass:
+			bits := fk.bits
+			if fk.signed {
+				bits--
+			}
+			return bits <= mantissaBits(to)
+		}
+	case decimalClass:
+		if classOf(to) == decimalClass {
+			return from.(decimaltype).scale <= to.(decimaltype).scale
+		}
+	case floatClass, complexClass:
+		switch classOf(to) {
+		case floatClass:
+			return classOf(from) == floatClass && mantissaBits(from) <= mantissaBits(to)
+		case complexClass:
+			return mantissaBits(from) <= mantissaBits(to)
+		}
+	}
+	return false
+}
+
+// Convert implicitly converts v into the Type t.  Values can be converted
+// if the conversion is lossless or if it is lossy but v itself can be
+// represented exactly by t.  Integers that are out of t's range result in an
+// *OverflowError and all other failures in a *ConversionError.
+func Convert(v Value, t Type) (Value, error) {
+	return convert(v, t, false)
+}
+
+// CastValue converts v into the Type t like a Cast expression would:  Lossy
+// and explicit conversions are allowed.
+func CastValue(v Value, t Type) (Value, error) {
+	return convert(v, t, true)
+}
+
+func convert(v Value, t Type, explicit bool) (Value, error) {
+	if vr, ok := v.(Var); ok {
+		v = vr.Value()
+	}
+	tv, ok := v.(TypedValueExpr)
+	if !ok {
+		return nil, conversionError(v, t, nil)
+	}
+	from := tv.Type()
+	if sameType(from, t) {
+		return v, nil
+	}
+	switch ConversionOf(from, t) {
+	case NoConversion:
+		return nil, conversionError(v, t, nil)
+	case ExplicitConversion:
+		if !explicit {
+			return nil, conversionError(v, t, nil)
+		}
+	}
+	switch classOf(t) {
+	case stringClass:
+		return formatValue(v), nil
+	case boolClass:
+		b, err := strconv.ParseBool(string(v.(String)))
+		if err != nil {
+			return nil, conversionError(v, t, err)
+		}
+		return Bool(b), nil
+	case timeClass:
+		tm, err := ParseTime(string(v.(String)))
+		if err != nil {
+			return nil, conversionError(v, t, err)
+		}
+		return tm, nil
+	case durationClass:
+		if s, ok := v.(String); ok {
+			d, err := ParseDuration(string(s))
+			if err != nil {
+				return nil, conversionError(v, t, err)
+			}
+			return d, nil
+		}
+		ns, err := convert(v, Int64Type, explicit)
+		if err != nil {
+			return nil, err
+		}
+		return Duration(ns.(Int64)), nil
+	}
+	return convertNumber(v, t, explicit)
+}
+
+// formatValue represents v as a String.
+func formatValue(v Value) String {
+	switch v := v.(type) {
+	case String:
+		return v
+	case *Rational:
+		if r, ok := v.Value().(*Rational); ok {
+			return String((*big.Rat)(r).RatString())
+		}
+		return formatValue(v.Value())
+	case Dynamic:
+		if s, ok := v.callString(); ok {
+			return String(s)
+		}
+		if b, ok := v.basic(); ok {
+			return formatValue(b)
+		}
+		return String(v.String())
+	case fmt.Stringer:
+		return String(v.String())
+	}
+	return String(fmt.Sprint(v.Interface()))
+}
+
+// convertNumber converts v into the numeric Type t.
+func convertNumber(v Value, t Type, lossy bool) (Value, error) {
+	switch x := v.(type) {
+	case String:
+		if classOf(t) == complexClass {
+			c, err := strconv.ParseComplex(string(x), 128)
+			if err != nil {
+				return nil, conversionError(v, t, err)
+			}
+			return convertNumber(Complex128(c), t, lossy)
+		}
+		r, ok := new(big.Rat).SetString(string(x))
+		if !ok {
+			return nil, conversionError(v, t, nil)
+		}
+		return convertRat(v, r, t, lossy)
+	case Duration:
+		return convertNumber(Int64(x), t, lossy)
+	}
+	if c, ok := complexOf(v); ok && (isComplex(v) || isFloat(v)) {
+		return convertFloat(v, c, t, lossy)
+	}
+	n, ok := v.(Number)
+	if !ok {
+		return nil, conversionError(v, t, nil)
+	}
+	var r big.Rat
+	n.Rat(&r)
+	return convertRat(v, &r, t, lossy)
+}
+
+// convertFloat converts the float or complex value v whose value is c into
+// the numeric Type t.
+func convertFloat(v Value, c complex128, t Type, lossy bool) (Value, error) {
+	if imag(c) != 0 && classOf(t) != complexClass && !lossy {
+		return nil, conversionError(v, t, nil)
+	}
+	var result Value
+	exact := true
+	switch t.(type) {
+	case float32type:
+		f := float32(real(c))
+		result, exact = Float32(f), sameFloat(float64(f), real(c))
+	case float64type:
+		result = Float64(real(c))
+	case complex64type:
+		c64 := complex64(c)
+		result = Complex64(c64)
+		exact = sameFloat(float64(real(c64)), real(c)) && sameFloat(float64(imag(c64)), imag(c))
+	case complex128type:
+		result = Complex128(c)
+	default:
+		if math.IsInf(real(c), 0) || math.IsNaN(real(c)) {
+			return nil, conversionError(v, t, nil)
+		}
+		r := new(big.Rat)
+		r.SetFloat64(real(c))
+		return convertRat(v, r, t, lossy)
+	}
+	if !exact && !lossy {
+		return nil, conversionError(v, t, nil)
+	}
+	return result, nil
+}
+
+// sameFloat returns true if a and b are equal or both NaN.
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+// convertRat converts v whose value is r into the numeric Type t.
+func convertRat(v Value, r *big.Rat, t Type, lossy bool) (Value, error) {
+	switch t := t.(type) {
+	case rationaltype:
+		return (*Rational)(new(big.Rat).Set(r)), nil
+	case decimaltype:
+		d, err := DecimalFromRat(r, t.scale, t.mode)
+		if err != nil {
+			return nil, conversionError(v, t, err)
+		}
+		var dr big.Rat
+		d.Rat(&dr)
+		if dr.Cmp(r) != 0 && !lossy {
+			return nil, conversionError(v, t, nil)
+		}
+		return d, nil
+	case float32type:
+		f, exact := r.Float32()
+		if !exact && !lossy {
+			return nil, conversionError(v, t, nil)
+		}
+		return Float32(f), nil
+	case float64type, complex64type, complex128type:
+		f, exact := r.Float64()
+		if !exact && !lossy {
+			return nil, conversionError(v, t, nil)
+		}
+		return convertFloat(v, complex(f, 0), t, lossy)
+	}
+	if !r.IsInt() && !lossy {
+		return nil, conversionError(v, t, nil)
+	}
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+	if _, ok := t.(biginttype); ok {
+		return (*BigInt)(i), nil
+	}
+	like := t.Zero()
+	kind, ok := intKindOf(like)
+	if !ok {
+		return nil, conversionError(v, t, nil)
+	}
+	if min, max := kind.bounds(); i.Cmp(min) < 0 || i.Cmp(max) > 0 {
+		if !lossy {
+			return nil, overflow(t, v)
+		}
+		i = kind.wrap(i)
+	}
+	return makeInt(like, i), nil
+}
+
+// Cast explicitly converts the value of its expression into its Type.  See
+// CastValue.
+type Cast struct {
+	ValueExpr
+	Type Type
+}
+
+// Copy the expression.
+func (c Cast) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(Cast{
+		c.ValueExpr.Copy(transformations...).(ValueExpr),
+		c.Type,
+	}, transformations...)
+}
+
+// Eval evaluates the expression.
+func (c Cast) Eval() (interface{}, error) {
+	value, err := c.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression into a Value.
+func (c Cast) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), c)
+}
+
+// EvalValueContext evaluates the expression into a Value under ctx.
+func (c Cast) EvalValueContext(ctx context.Context) (Value, error) {
+	value, err := evalValueContext(ctx, c.ValueExpr)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := value.(Dynamic); ok && classOf(c.Type) == stringClass {
+		if err := d.checkString(PolicyFromContext(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	return CastValue(value, c.Type)
+}
+
+// Operand gets the expression whose value is converted.
+func (c Cast) Operand() Expr {
+	return c.ValueExpr
+}
+
+// String represents the expression as a string.
+func (c Cast) String() string {
+	return fmt.Sprintf("%s(%s)", typeName(c.Type), StringifyExpr(c.ValueExpr, false))
+}
@@ -0,0 +1,155 @@
+package expr_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/skillian/expr"
+)
+
+func TestConversionOf(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		from, to expr.Type
+		expect   expr.Conversion
+	}{
+		{expr.IntType, expr.IntType, expr.LosslessConversion},
+		{expr.Int8Type, expr.Int64Type, expr.LosslessConversion},
+		{expr.Int64Type, expr.Int8Type, expr.LossyConversion},
+		{expr.Uint32Type, expr.Int64Type, expr.LosslessConversion},
+		{expr.Int32Type, expr.Uint64Type, expr.LossyConversion},
+		{expr.Int32Type, expr.Float64Type, expr.LosslessConversion},
+		{expr.Int64Type, expr.Float64Type, expr.LossyConversion},
+		{expr.Float32Type, expr.Float64Type, expr.LosslessConversion},
+		{expr.Float64Type, expr.IntType, expr.LossyConversion},
+		{expr.Int64Type, expr.BigIntType, expr.LosslessConversion},
+		{expr.DecimalType(2, expr.RoundHalfEven), expr.DecimalType(4, expr.RoundDown), expr.LosslessConversion},
+		{expr.DecimalType(4, expr.RoundHalfEven), expr.DecimalType(2, expr.RoundHalfEven), expr.LossyConversion},
+		{expr.Float64Type, expr.Complex128Type, expr.LosslessConversion},
+		{expr.Complex128Type, expr.Float64Type, expr.LossyConversion},
+		{expr.StringType, expr.IntType, expr.ExplicitConversion},
+		{expr.BoolType, expr.IntType, expr.NoConversion},
+		{expr.TimeType, expr.DurationType, expr.NoConversion},
+	}
+	for _, tc := range tcs {
+		if c := expr.ConversionOf(tc.from, tc.to); c != tc.expect {
+			t.Errorf("%v -> %v: expected %v conversion but got %v",
+				tc.from.Zero(), tc.to.Zero(), tc.expect, c)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		v      expr.Value
+		to     expr.Type
+		expect expr.Value
+		err    interface{}
+	}{
+		{expr.Int64(5), expr.IntType, expr.Int(5), nil},
+		{expr.Int64(-1), expr.Uint8Type, nil, new(*expr.OverflowError)},
+		{expr.Int(300), expr.Int8Type, nil, new(*expr.OverflowError)},
+		{expr.Float64(2), expr.Int32Type, expr.Int32(2), nil},
+		{expr.Float64(2.5), expr.Int32Type, nil, new(*expr.ConversionError)},
+		{expr.Float64(math.NaN()), expr.IntType, nil, new(*expr.ConversionError)},
+		{expr.Int64(1<<53 + 1), expr.Float64Type, nil, new(*expr.ConversionError)},
+		{expr.Int64(1 << 53), expr.Float64Type, expr.Float64(1 << 53), nil},
+		{(*expr.Rational)(big.NewRat(1, 4)), expr.Float32Type, expr.Float32(0.25), nil},
+		{(*expr.Rational)(big.NewRat(1, 3)), expr.DecimalType(2, expr.RoundHalfEven), nil, new(*expr.ConversionError)},
+		{expr.Complex128(3), expr.IntType, expr.Int(3), nil},
+		{expr.Complex128(3 + 1i), expr.IntType, nil, new(*expr.ConversionError)},
+		{expr.String("12"), expr.IntType, nil, new(*expr.ConversionError)},
+		{expr.Bool(true), expr.IntType, nil, new(*expr.ConversionError)},
+	}
+	for _, tc := range tcs {
+		v, err := expr.Convert(tc.v, tc.to)
+		if tc.err != nil {
+			if !errors.As(err, tc.err) {
+				t.Errorf("Convert(%v (type: %T), %T): expected %T but got %v (result: %v)",
+					tc.v, tc.v, tc.to.Zero(), tc.err, err, v)
+			}
+			continue
+		}
+		if err != nil || v != tc.expect {
+			t.Errorf("Convert(%v (type: %T), %T) -> %v (type: %T) (err: %v)",
+				tc.v, tc.v, tc.to.Zero(), v, v, err)
+		}
+	}
+	var i expr.Int
+	if err := i.SetValue(expr.Int64(42)); err != nil || i != 42 {
+		t.Errorf("SetValue(Int64(42)) -> %v (err: %v)", i, err)
+	}
+	var f expr.Float64
+	if err := f.SetValue(expr.Int(3)); err != nil || f != 3 {
+		t.Errorf("SetValue(Int(3)) -> %v (err: %v)", f, err)
+	}
+	var u expr.Uint64
+	var oe *expr.OverflowError
+	if err := u.SetValue(expr.Int64(-1)); !errors.As(err, &oe) {
+		t.Errorf("expected *OverflowError but got %v", err)
+	}
+}
+
+func TestSetValueFromVar(t *testing.T) {
+	t.Parallel()
+	values := []expr.TypedValueExpr{
+		expr.Int(1), expr.Int8(2), expr.Int16(3), expr.Int32(4), expr.Int64(5),
+		expr.Uint(6), expr.Uint8(7), expr.Uint16(8), expr.Uint32(9), expr.Uint64(10),
+		expr.Float32(1.5), expr.Float64(2.5), expr.Complex64(1 + 2i), expr.Complex128(3 + 4i),
+		expr.Bool(true), expr.String("a"), expr.NewDecimal(125, 2),
+		expr.Time(time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)), expr.Duration(time.Minute),
+	}
+	for _, v := range values {
+		src, dst := v.Type().Var(), v.Type().Var()
+		if err := src.SetValue(v.(expr.Value)); err != nil {
+			t.Errorf("%T: %v", v, err)
+			continue
+		}
+		if err := dst.SetValue(src.(expr.Value)); err != nil {
+			t.Errorf("%T: %v", src, err)
+			continue
+		}
+		if eq, err := expr.EqualValues(dst.Value(), v.(expr.Value)); err != nil || !eq {
+			t.Errorf("%T: expected %v but got %v (err: %v)", dst, v, dst.Value(), err)
+		}
+	}
+}
+
+func TestCast(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		expr.Cast
+		expect expr.Value
+	}{
+		{expr.Cast{expr.Float64(-2.7), expr.IntType}, expr.Int(-2)},
+		{expr.Cast{expr.Int(300), expr.Uint8Type}, expr.Uint8(44)},
+		{expr.Cast{expr.Int(-1), expr.Uint16Type}, expr.Uint16(math.MaxUint16)},
+		{expr.Cast{expr.Div{expr.Int(1), expr.Int(3)}, expr.DecimalType(2, expr.RoundHalfEven)}, expr.NewDecimal(33, 2)},
+		{expr.Cast{expr.Int(42), expr.StringType}, expr.String("42")},
+		{expr.Cast{expr.Div{expr.Int(1), expr.Int(3)}, expr.StringType}, expr.String("1/3")},
+		{expr.Cast{expr.String("1.5"), expr.Float64Type}, expr.Float64(1.5)},
+		{expr.Cast{expr.String("1.5"), expr.IntType}, expr.Int(1)},
+		{expr.Cast{expr.String("true"), expr.BoolType}, expr.True},
+		{expr.Cast{expr.String("30d"), expr.DurationType}, expr.Duration(30 * 24 * time.Hour)},
+		{expr.Cast{expr.Duration(time.Second), expr.Int64Type}, expr.Int64(time.Second)},
+		{expr.Cast{expr.Complex128(2 + 3i), expr.Float64Type}, expr.Float64(2)},
+	}
+	for _, tc := range tcs {
+		v, err := tc.Cast.EvalValue()
+		if err != nil {
+			t.Errorf("%v: %v", tc.Cast, err)
+			continue
+		}
+		if cmp, err := expr.Cmp(v, tc.expect); err != nil || cmp != 0 {
+			t.Errorf("%v -> %v (type: %T) (expected %v (type: %T))",
+				tc.Cast, v, v, tc.expect, tc.expect)
+		}
+	}
+	if _, err := (expr.Cast{expr.String("twelve"), expr.IntType}).EvalValue(); err == nil {
+		t.Errorf("expected casting \"twelve\" to an Int to fail")
+	}
+}
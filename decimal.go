@@ -0,0 +1,286 @@
+package expr
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/skillian/errors"
+)
+
+// RoundingMode selects how a Decimal is rounded when a result has more
+// digits after the decimal point than its scale allows.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest neighbor and ties to the even
+	// neighbor (banker's rounding).
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest neighbor and ties away from zero.
+	RoundHalfUp
+
+	// RoundDown truncates towards zero.
+	RoundDown
+)
+
+// Decimal is an arbitrary-precision decimal number with a fixed number of
+// digits after the decimal point (its scale).
+//
+// Arithmetic between a Decimal and any other Number produces a Decimal whose
+// scale is the largest scale of its Decimal operands.  Exact results that
+// need more digits are rounded with the RoundingMode of the left-most Decimal
+// operand.
+type Decimal struct {
+	// unscaled is the value of the Decimal multiplied by 10^scale.
+	unscaled *big.Int
+	scale    int
+	mode     RoundingMode
+}
+
+type decimaltype struct {
+	scale int
+	mode  RoundingMode
+}
+
+// DecimalType gets the expression Type of Decimals with the given scale and
+// RoundingMode.
+func DecimalType(scale int, mode RoundingMode) Type {
+	return decimaltype{scale, mode}
+}
+
+// Zero gets the zero Decimal with the type's scale and rounding mode.
+func (t decimaltype) Zero() Value {
+	return Decimal{new(big.Int), t.scale, t.mode}
+}
+
+// Var creates a Decimal variable.
+func (t decimaltype) Var() Var {
+	d := t.Zero().(Decimal)
+	return &d
+}
+
+// NewDecimal creates a Decimal whose value is unscaled / 10^scale and that
+// rounds half to even.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{big.NewInt(unscaled), scale, RoundHalfEven}
+}
+
+// ParseDecimal parses a decimal number such as "-12.30".  The scale of the
+// result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	scale := 0
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		scale = len(digits) - i - 1
+	}
+	if len(s)-len(digits) > 1 || strings.IndexFunc(digits, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	}) >= 0 {
+		return Decimal{}, errors.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, errors.Errorf("invalid decimal %q", s)
+	}
+	return DecimalFromRat(r, scale, RoundHalfEven)
+}
+
+// DecimalFromRat creates a Decimal with the given scale from r, rounding
+// according to mode.  The scale cannot be negative.
+func DecimalFromRat(r *big.Rat, scale int, mode RoundingMode) (Decimal, error) {
+	if scale < 0 {
+		return Decimal{}, errors.Errorf("invalid negative decimal scale %d", scale)
+	}
+	x := new(big.Rat).Set(r)
+	x.Mul(x, new(big.Rat).SetInt(pow10(scale)))
+	q, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if rem.Sign() != 0 && mode != RoundDown {
+		// compare twice the remainder to the denominator to find out
+		// which neighbor is nearer:
+		half := new(big.Int).Abs(rem)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(x.Denom())
+		if cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || q.Bit(0) == 1)) {
+			q.Add(q, big.NewInt(int64(x.Sign())))
+		}
+	}
+	return Decimal{q, scale, mode}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// int gets the unscaled value of the Decimal.
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale gets the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Rounding gets the Decimal's RoundingMode.
+func (d Decimal) Rounding() RoundingMode {
+	return d.mode
+}
+
+// Rescale creates a copy of d with the given scale, rounding according to
+// d's RoundingMode if the scale is reduced.  The scale cannot be negative.
+func (d Decimal) Rescale(scale int) (Decimal, error) {
+	var r big.Rat
+	d.Rat(&r)
+	return DecimalFromRat(&r, scale, d.mode)
+}
+
+// WithRounding creates a copy of d with the given RoundingMode.
+func (d Decimal) WithRounding(mode RoundingMode) Decimal {
+	return Decimal{new(big.Int).Set(d.int()), d.scale, mode}
+}
+
+// Copy the expression.
+func (d Decimal) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(d.WithRounding(d.mode), transformations...)
+}
+
+// Cmp compares d to another Number.
+func (d Decimal) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(d, n)
+	}
+	return 0, notComparable(d, v, nil)
+}
+
+// Eval the expression.
+func (d Decimal) Eval() (interface{}, error) {
+	return d.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (d Decimal) EvalValue() (Value, error) {
+	return d, nil
+}
+
+// Interface gets the Decimal itself.
+func (d Decimal) Interface() interface{} {
+	return d
+}
+
+// Type gets the Decimal's expression Type.
+func (d Decimal) Type() Type {
+	return decimaltype{d.scale, d.mode}
+}
+
+// Rat stores d's value into r.
+func (d Decimal) Rat(r *big.Rat) {
+	r.SetFrac(d.int(), pow10(d.scale))
+}
+
+// EvalAdd adds v to d.
+func (d Decimal) EvalAdd(v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return decimalArithmeticHelper(d, n, (*big.Rat).Add)
+	}
+	return nil, typeMismatch("add", d, v)
+}
+
+// EvalSubtract subtracts v from d.
+func (d Decimal) EvalSubtract(v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return decimalArithmeticHelper(d, n, (*big.Rat).Sub)
+	}
+	return nil, typeMismatch("subtract", d, v)
+}
+
+// EvalMultiply multiplies d by v.
+func (d Decimal) EvalMultiply(v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return decimalArithmeticHelper(d, n, (*big.Rat).Mul)
+	}
+	return nil, typeMismatch("multiply", d, v)
+}
+
+// EvalDivide divides d by v.
+func (d Decimal) EvalDivide(v Value) (Value, error) {
+	return d.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides d by v under ctx.
+func (d Decimal) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	if n, ok := v.(Number); ok {
+		return numberDivideHelper(ctx, d, n)
+	}
+	return nil, typeMismatch("divide", d, v)
+}
+
+// Value of the Decimal variable.
+func (d *Decimal) Value() Value {
+	return *d
+}
+
+// SetValue sets the Decimal variable to v, rounding it to the variable's
+// scale.
+func (d *Decimal) SetValue(v Value) error {
+	n, ok := v.(Number)
+	if !ok {
+		return conversionError(v, d.Type(), nil)
+	}
+	var r big.Rat
+	if err := numberRat(&r, n); err != nil {
+		return conversionError(v, d.Type(), nil)
+	}
+	rescaled, err := DecimalFromRat(&r, d.scale, d.mode)
+	if err != nil {
+		return conversionError(v, d.Type(), err)
+	}
+	*d = rescaled
+	return nil
+}
+
+// String represents the Decimal with exactly Scale digits after the decimal
+// point.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		point := len(digits) - d.scale
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if d.int().Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// decimalOperands gets the scale and rounding mode of the result of an
+// operation between left and right if either is a Decimal.
+func decimalOperands(left, right Value) (scale int, mode RoundingMode, ok bool) {
+	if d, isDec := right.(Decimal); isDec {
+		scale, mode, ok = d.scale, d.mode, true
+	}
+	if d, isDec := left.(Decimal); isDec {
+		if !ok || d.scale > scale {
+			scale = d.scale
+		}
+		mode, ok = d.mode, true
+	}
+	return
+}
+
+// decimalArithmeticHelper performs an operation on two Numbers where at least
+// one of them is a Decimal.
+func decimalArithmeticHelper(left, right Number, bigRatFunc func(*big.Rat, *big.Rat, *big.Rat) *big.Rat) (Value, error) {
+	scale, mode, _ := decimalOperands(left, right)
+	var rats [2]big.Rat
+	if err := numberRats(&rats, left, right); err != nil {
+		return nil, err
+	}
+	return DecimalFromRat(bigRatFunc(new(big.Rat), &rats[0], &rats[1]), scale, mode)
+}
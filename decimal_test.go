@@ -0,0 +1,127 @@
+package expr_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func mustParseDecimal(t *testing.T, s string) expr.Decimal {
+	t.Helper()
+	d, err := expr.ParseDecimal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDecimalRounding(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		value    string
+		mode     expr.RoundingMode
+		expected string
+	}{
+		{"2.345", expr.RoundHalfEven, "2.34"},
+		{"2.355", expr.RoundHalfEven, "2.36"},
+		{"2.345", expr.RoundHalfUp, "2.35"},
+		{"2.349", expr.RoundDown, "2.34"},
+		{"-2.345", expr.RoundHalfEven, "-2.34"},
+		{"-2.345", expr.RoundHalfUp, "-2.35"},
+		{"-2.349", expr.RoundDown, "-2.34"},
+		{"0.005", expr.RoundHalfUp, "0.01"},
+		{"-0.004", expr.RoundHalfUp, "0.00"},
+	}
+	for _, tc := range tcs {
+		d, err := mustParseDecimal(t, tc.value).WithRounding(tc.mode).Rescale(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.String() != tc.expected {
+			t.Errorf("rounding %s with mode %d: expected %s but got %s",
+				tc.value, tc.mode, tc.expected, d)
+		}
+	}
+}
+
+func TestDecimalNegativeScale(t *testing.T) {
+	t.Parallel()
+	if d, err := expr.DecimalFromRat(big.NewRat(1, 2), -1, expr.RoundHalfEven); err == nil {
+		t.Errorf("expected an error but got %v", d)
+	}
+	if d, err := mustParseDecimal(t, "1.25").Rescale(-2); err == nil {
+		t.Errorf("expected an error but got %v", d)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+	price := mustParseDecimal(t, "10.00")
+	tcs := []struct {
+		expr.ValueExpr
+		expected string
+	}{
+		{expr.Add{mustParseDecimal(t, "0.10"), mustParseDecimal(t, "0.20")}, "0.30"},
+		{expr.Sub{price, mustParseDecimal(t, "0.015")}, "9.985"},
+		{expr.Mul{price, expr.Int(3)}, "30.00"},
+		{expr.Add{expr.Int(1), price}, "11.00"},
+		{expr.Div{price, expr.Int(3)}, "3.33"},
+		{expr.Mul{mustParseDecimal(t, "19.99"), mustParseDecimal(t, "0.07")}, "1.40"},
+		{expr.Add{(*expr.Rational)(big.NewRat(1, 3)), price}, "10.33"},
+		{expr.Mul{expr.Float64(0.5), price}, "5.00"},
+	}
+	for _, tc := range tcs {
+		v, err := tc.ValueExpr.EvalValue()
+		if err != nil {
+			t.Errorf("%v: %v", tc.ValueExpr, err)
+			continue
+		}
+		d, ok := v.(expr.Decimal)
+		if !ok {
+			t.Errorf("%v -> %v (type: %T) is not a Decimal", tc.ValueExpr, v, v)
+			continue
+		}
+		if d.String() != tc.expected {
+			t.Errorf("%v -> %v (expected %v)", tc.ValueExpr, d, tc.expected)
+		}
+	}
+	_, err := expr.Div{price, mustParseDecimal(t, "0.00")}.EvalValue()
+	var dz *expr.DivisionByZeroError
+	if !errors.As(err, &dz) {
+		t.Errorf("expected *DivisionByZeroError but got %v", err)
+	}
+	if cmp, err := expr.Cmp(price, expr.Int(10)); err != nil || cmp != 0 {
+		t.Errorf("expected %v to equal 10 (cmp: %d, err: %v)", price, cmp, err)
+	}
+}
+
+func TestDecimalVar(t *testing.T) {
+	t.Parallel()
+	v := expr.DecimalType(2, expr.RoundHalfUp).Var()
+	if err := v.SetValue((*expr.Rational)(big.NewRat(2, 3))); err != nil {
+		t.Fatal(err)
+	}
+	if s := v.Value().(expr.Decimal).String(); s != "0.67" {
+		t.Errorf("expected 0.67 but got %s", s)
+	}
+	if _, err := expr.ParseDecimal("1e3"); err == nil {
+		t.Error("expected error parsing 1e3")
+	}
+	for _, x := range []expr.Value{
+		expr.Float64(math.Inf(1)),
+		expr.Float32(float32(math.NaN())),
+		expr.String("1.50"),
+		expr.True,
+	} {
+		var ce *expr.ConversionError
+		if err := v.SetValue(x); !errors.As(err, &ce) {
+			t.Errorf("%v: expected *ConversionError but got %v", x, err)
+		}
+		if s := v.Value().(expr.Decimal).String(); s != "0.67" {
+			t.Errorf("%v: failed SetValue changed the variable to %s", x, s)
+		}
+	}
+}
@@ -1,12 +1,31 @@
 package expr
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/skillian/errors"
 )
 
 // Dynamic values can hold any Go value (it wraps reflect.Value)
+//
+// Dynamic values that hold a Go type whose underlying type is a bool, string
+// or number (e.g. `type Celsius float64`) are compared and used in arithmetic
+// like the corresponding Value (e.g. a Float64).  The results of arithmetic
+// are Values of those corresponding types.
+//
+// Other Go types can be used as operands by implementing conventional
+// methods:  `Cmp(T) int` or `Compare(T) int` for comparisons, `Equal(T)
+// bool` for equality, `Add(T) T`, `Sub(T) T`, `Mul(T) T`, `Div(T) T`,
+// `Mod(T) T` and `Pow(T) T` for arithmetic (optionally also returning an
+// error) and `String() string` to be cast as a String.
+// Evaluations under a context with a Policy only call the methods that the
+// Policy allows.
 type Dynamic reflect.Value
 
 type dynamictype struct {
@@ -17,18 +36,126 @@ func (t dynamictype) Zero() Value {
 	return Dynamic(reflect.Zero(t.Type))
 }
 
+// Var creates a DynamicVar of the type.
 func (t dynamictype) Var() Var {
-	return new(Dynamic)
+	return NewDynamicVar(t.Type)
+}
+
+// CopyMode selects how Dynamic values are copied.
+type CopyMode int
+
+const (
+	// ShallowCopy copies the Go value like an assignment does so pointers,
+	// maps and slices within the copy refer to the same data as the
+	// original.  This is the default.
+	ShallowCopy CopyMode = iota
+
+	// DeepCopy recursively copies the data referred to by pointers, maps,
+	// slices and interfaces.  Unexported struct fields are copied
+	// shallowly.
+	DeepCopy
+)
+
+var copyModes sync.Map
+
+// SetCopyMode sets how Dynamic values of type t are copied.
+func SetCopyMode(t reflect.Type, mode CopyMode) {
+	copyModes.Store(t, mode)
+}
+
+// CopyModeOf gets how Dynamic values of type t are copied.
+func CopyModeOf(t reflect.Type) CopyMode {
+	mode, _ := copyModes.Load(t)
+	m, _ := mode.(CopyMode)
+	return m
 }
 
 func (d Dynamic) copy() Dynamic {
 	v := reflect.Value(d)
-	var r reflect.Value
+	if !v.IsValid() || !v.CanInterface() {
+		return d
+	}
+	if CopyModeOf(v.Type()) == DeepCopy {
+		return Dynamic(deepCopy(v, make(map[copyKey]reflect.Value)))
+	}
+	r := reflect.New(v.Type()).Elem()
 	r.Set(v)
 	return Dynamic(r)
 }
 
-// Copy this Dynamic value
+// copyKey identifies a pointer or map that deepCopy already copied.
+// reflect.Values cannot be used as keys because equal pointers can be held
+// by different reflect.Values.
+type copyKey struct {
+	t reflect.Type
+	p uintptr
+}
+
+// deepCopy recursively copies v.  seen maps the pointers and maps that were
+// already copied to their copies so that cycles and aliases are preserved.
+func deepCopy(v reflect.Value, seen map[copyKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		k := copyKey{v.Type(), v.Pointer()}
+		if c, ok := seen[k]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		seen[k] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+		return c
+	case reflect.Interface:
+		c := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			c.Set(deepCopy(v.Elem(), seen))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		k := copyKey{v.Type(), v.Pointer()}
+		if c, ok := seen[k]; ok {
+			return c
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		seen[k] = c
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(deepCopy(it.Key(), seen), deepCopy(it.Value(), seen))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i), seen))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+// Copy this Dynamic value according to its type's CopyMode.
 func (d Dynamic) Copy(transformations ...Mapper) Expr {
 	return ApplyMappers(d.copy(), transformations...)
 }
@@ -56,6 +183,101 @@ func (d Dynamic) Interface() interface{} {
 	return result
 }
 
+// String represents the Dynamic value as a string like fmt's %v verb, but
+// without calling any of the value's methods so that representing the value
+// (e.g. in an error message) cannot bypass a Policy.  Casting the value to a
+// String uses its String method.
+func (d Dynamic) String() string {
+	v := reflect.Value(d)
+	if !v.IsValid() {
+		return v.String()
+	}
+	var sb strings.Builder
+	writeValue(&sb, v, 0)
+	return sb.String()
+}
+
+// writeValue writes v into sb like fmt's %v verb without calling v's
+// methods.  Pointers below the top level are written as addresses.
+func writeValue(sb *strings.Builder, v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		sb.WriteString("<nil>")
+	case reflect.Bool:
+		sb.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sb.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		if v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128 {
+			fmt.Fprint(sb, v.Complex())
+		} else {
+			fmt.Fprint(sb, v.Float())
+		}
+	case reflect.String:
+		sb.WriteString(v.String())
+	case reflect.Ptr:
+		if v.IsNil() {
+			sb.WriteString("<nil>")
+			return
+		}
+		if depth > 0 {
+			fmt.Fprintf(sb, "0x%x", v.Pointer())
+			return
+		}
+		sb.WriteString("&")
+		writeValue(sb, v.Elem(), depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("<nil>")
+			return
+		}
+		writeValue(sb, v.Elem(), depth)
+	case reflect.Struct:
+		sb.WriteString("{")
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			writeValue(sb, v.Field(i), depth+1)
+		}
+		sb.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		sb.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			writeValue(sb, v.Index(i), depth+1)
+		}
+		sb.WriteString("]")
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			writeValue(&entry, iter.Key(), depth+1)
+			entry.WriteString(":")
+			writeValue(&entry, iter.Value(), depth+1)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		sb.WriteString("map[" + strings.Join(entries, " ") + "]")
+	default:
+		fmt.Fprintf(sb, "0x%x", v.Pointer())
+	}
+}
+
+// callString calls d's String method.  ok is false if d has no String
+// method.
+func (d Dynamic) callString() (s string, ok bool) {
+	if m, ok := d.methodOf("String"); ok && m.Type() == stringMethodType {
+		return m.Call(nil)[0].String(), true
+	}
+	return "", false
+}
+
 // Type gets the dynamic type of the value.
 func (d Dynamic) Type() Type {
 	return dynamictype{reflect.Value(d).Type()}
@@ -74,35 +296,86 @@ func (d *Dynamic) SetValue(v Value) error {
 
 // GetAttr gets an attribute from this Dynamic value
 func (d Dynamic) GetAttr(name String) (Value, error) {
+	return d.getAttr(nil, name)
+}
+
+// getAttr gets an attribute from this Dynamic value if the Policy allows it.
+func (d Dynamic) getAttr(p *Policy, name String) (Value, error) {
 	n := string(name)
 	v := reflect.Value(d)
-	if v.Kind() == reflect.Struct {
-		if f := v.FieldByName(n); f.IsValid() {
-			return ValueOf(f.Interface()), nil
+	sv := v
+	if sv.Kind() == reflect.Ptr && !sv.IsNil() && sv.Elem().Kind() == reflect.Struct {
+		sv = sv.Elem()
+	}
+	if sv.Kind() == reflect.Struct {
+		if sf, ok := sv.Type().FieldByName(n); ok && sf.PkgPath == "" {
+			if err := p.checkField(sv.Type(), sf); err != nil {
+				return nil, err
+			}
+			return ValueOf(sv.FieldByIndex(sf.Index).Interface()), nil
 		}
 	}
 	if m := v.MethodByName(n); m.IsValid() {
-		results := valuesInterfaces(m.Call(nil))
-		if len(results) > 2 {
-			return nil, errors.Errorf(
-				"method %s of %v returned %d values: %v",
-				n, v, len(results), results)
-		}
-		if len(results) == 1 {
-			return ValueOf(results[0]), nil
+		if err := p.checkMethod(v.Type(), n); err != nil {
+			return nil, err
 		}
-		err, ok := results[1].(error)
-		if !ok {
+		mt := m.Type()
+		switch {
+		case mt.NumIn() != 0:
+			return nil, errors.Errorf(
+				"method %s of %v requires %d arguments",
+				n, v, mt.NumIn())
+		case mt.NumOut() == 0 || mt.NumOut() > 2:
+			return nil, errors.Errorf(
+				"method %s of %v returns %d values",
+				n, v, mt.NumOut())
+		case mt.NumOut() == 2 && mt.Out(1) != errorType:
 			return nil, errors.Errorf(
-				"second result of method %s of %v is of type %T, not error",
-				name, v, results[1])
+				"second result of method %s of %v is of type %v, not error",
+				n, v, mt.Out(1))
 		}
-		if err != nil {
-			return nil, err
+		results := valuesInterfaces(m.Call(nil))
+		if len(results) == 2 && results[1] != nil {
+			return nil, results[1].(error)
 		}
 		return ValueOf(results[0]), nil
 	}
-	return nil, errors.Errorf("%v has no attribute %q", v, n)
+	return nil, unknownAttribute(d, n)
+}
+
+// setAttr sets the exported field called name of the struct that d points
+// to.
+func (d Dynamic) setAttr(p *Policy, name String, value Value) error {
+	n := string(name)
+	v := reflect.Value(d)
+	sv := v
+	if sv.Kind() == reflect.Ptr && !sv.IsNil() && sv.Elem().Kind() == reflect.Struct {
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct {
+		return unknownAttribute(d, n)
+	}
+	sf, ok := sv.Type().FieldByName(n)
+	if !ok || sf.PkgPath != "" {
+		return unknownAttribute(d, n)
+	}
+	if err := p.checkField(sv.Type(), sf); err != nil {
+		return err
+	}
+	f, err := sv.FieldByIndexErr(sf.Index)
+	if err != nil {
+		return err
+	}
+	if !f.CanSet() {
+		return errors.Errorf(
+			"cannot set field %s of %v because it is not addressable", n, d)
+	}
+	dv := NewDynamicVar(f.Type())
+	if err = dv.SetValue(value); err != nil {
+		return err
+	}
+	f.Set(reflect.Value(dv.value))
+	return nil
 }
 
 func valuesInterfaces(values []reflect.Value) []interface{} {
@@ -112,3 +385,365 @@ func valuesInterfaces(values []reflect.Value) []interface{} {
 	}
 	return results
 }
+
+// basicTypes are the basic Go types that Dynamic values of the same kinds
+// are converted into to be compared or used in arithmetic.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:       reflect.TypeOf(false),
+	reflect.String:     reflect.TypeOf(""),
+	reflect.Int:        reflect.TypeOf(int(0)),
+	reflect.Int8:       reflect.TypeOf(int8(0)),
+	reflect.Int16:      reflect.TypeOf(int16(0)),
+	reflect.Int32:      reflect.TypeOf(int32(0)),
+	reflect.Int64:      reflect.TypeOf(int64(0)),
+	reflect.Uint:       reflect.TypeOf(uint(0)),
+	reflect.Uint8:      reflect.TypeOf(uint8(0)),
+	reflect.Uint16:     reflect.TypeOf(uint16(0)),
+	reflect.Uint32:     reflect.TypeOf(uint32(0)),
+	reflect.Uint64:     reflect.TypeOf(uint64(0)),
+	reflect.Float32:    reflect.TypeOf(float32(0)),
+	reflect.Float64:    reflect.TypeOf(float64(0)),
+	reflect.Complex64:  reflect.TypeOf(complex64(0)),
+	reflect.Complex128: reflect.TypeOf(complex128(0)),
+}
+
+// basic gets the Value of the basic Go type underlying d's type, e.g. a
+// Float64 for a Dynamic value holding a `type Celsius float64`.
+func (d Dynamic) basic() (Value, bool) {
+	v := reflect.Value(d)
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	bt, ok := basicTypes[v.Kind()]
+	if !ok {
+		return nil, false
+	}
+	return ValueOf(v.Convert(bt).Interface()), true
+}
+
+// unwrapDynamics replaces Dynamic values of basic kinds with their basic
+// Values.
+func unwrapDynamics(left, right Value) (Value, Value) {
+	return unwrapDynamic(left), unwrapDynamic(right)
+}
+
+func unwrapDynamic(v Value) Value {
+	if d, ok := v.(Dynamic); ok {
+		if b, ok := d.basic(); ok {
+			return b
+		}
+	}
+	return v
+}
+
+var (
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+	stringMethodType = reflect.TypeOf(func() string { return "" })
+)
+
+// methodOf gets d's method called name.  Methods with pointer receivers are
+// called on a copy of d's value.
+func (d Dynamic) methodOf(name string) (reflect.Value, bool) {
+	v := reflect.Value(d)
+	if !v.IsValid() || !v.CanInterface() {
+		return reflect.Value{}, false
+	}
+	if m := v.MethodByName(name); m.IsValid() {
+		return m, true
+	}
+	if v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		if m := p.MethodByName(name); m.IsValid() {
+			return m, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// operator calls the first of d's methods with the given names that takes
+// a single argument that v can be passed as and that returns a single result,
+// optionally followed by an error.  ok is false if there is no such method.
+// If the Policy p denies calling the method, ok is true and err is an
+// *AccessError.
+func (d Dynamic) operator(p *Policy, v Value, names ...string) (result reflect.Value, ok bool, err error) {
+	for _, name := range names {
+		m, ok := d.methodOf(name)
+		if !ok {
+			continue
+		}
+		mt := m.Type()
+		if mt.NumIn() != 1 || mt.IsVariadic() {
+			continue
+		}
+		withErr := mt.NumOut() == 2 && mt.Out(1) == errorType
+		if mt.NumOut() != 1 && !withErr {
+			continue
+		}
+		arg, ok := operatorArg(v, mt.In(0))
+		if !ok {
+			continue
+		}
+		if err := p.checkMethod(reflect.Value(d).Type(), name); err != nil {
+			return reflect.Value{}, true, err
+		}
+		results := m.Call([]reflect.Value{arg})
+		if withErr && !results[1].IsNil() {
+			return reflect.Value{}, true, results[1].Interface().(error)
+		}
+		return results[0], true, nil
+	}
+	return reflect.Value{}, false, nil
+}
+
+// operatorArg gets v as an argument of type t.
+func operatorArg(v Value, t reflect.Type) (reflect.Value, bool) {
+	var rv reflect.Value
+	if dv, ok := v.(Dynamic); ok {
+		rv = reflect.Value(dv)
+	} else {
+		rv = reflect.ValueOf(v.Interface())
+	}
+	if !rv.IsValid() || !rv.CanInterface() {
+		return reflect.Value{}, false
+	}
+	switch {
+	case rv.Type().AssignableTo(t):
+		return rv, true
+	case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Type().Elem().AssignableTo(t):
+		return rv.Elem(), true
+	case t.Kind() == reflect.Ptr && rv.Type().AssignableTo(t.Elem()):
+		p := reflect.New(t.Elem())
+		p.Elem().Set(rv)
+		return p, true
+	}
+	return reflect.Value{}, false
+}
+
+// Cmp compares d to another value.  Dynamic values of basic kinds are
+// compared like their corresponding Values.  Otherwise, d's value must have
+// a `Cmp(T) int` or `Compare(T) int` method whose argument v can be passed
+// as.
+func (d Dynamic) Cmp(v Value) (int, error) {
+	return d.cmp(nil, v)
+}
+
+// cmp compares d to v under the Policy p.
+func (d Dynamic) cmp(p *Policy, v Value) (int, error) {
+	if b, ok := d.basic(); ok {
+		return cmpValues(p, b, v)
+	}
+	result, ok, err := d.operator(p, v, "Cmp", "Compare")
+	if err != nil || !ok {
+		return 0, notComparable(d, v, err)
+	}
+	switch result.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch i := result.Int(); {
+		case i < 0:
+			return -1, nil
+		case i > 0:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, notComparable(d, v, nil)
+}
+
+// Equal checks if d is equal to v.  Dynamic values of basic kinds are
+// compared like their corresponding Values.  Otherwise, d's value is compared
+// with its `Equal(T) bool` method, its Cmp method or, if v is a Dynamic of
+// the same type, reflect.DeepEqual.
+func (d Dynamic) Equal(v Value) (bool, error) {
+	return d.equal(nil, v)
+}
+
+// equal checks if d is equal to v under the Policy p.
+func (d Dynamic) equal(p *Policy, v Value) (bool, error) {
+	if b, ok := d.basic(); ok {
+		return equalValues(p, b, v)
+	}
+	result, ok, err := d.operator(p, v, "Equal")
+	if err != nil {
+		return false, notComparable(d, v, err)
+	}
+	if ok && result.Kind() == reflect.Bool {
+		return result.Bool(), nil
+	}
+	cmp, err := d.cmp(p, v)
+	if err == nil {
+		return cmp == 0, nil
+	}
+	if o, ok := v.(Dynamic); ok && reflect.Value(d).Type() == reflect.Value(o).Type() {
+		return reflect.DeepEqual(d.Interface(), o.Interface()), nil
+	}
+	return false, err
+}
+
+// checkString checks if the Policy p allows calling d's String method when
+// d is cast to a String.
+func (d Dynamic) checkString(p *Policy) error {
+	if m, ok := d.methodOf("String"); ok && m.Type() == stringMethodType {
+		return p.checkMethod(reflect.Value(d).Type(), "String")
+	}
+	return nil
+}
+
+// arithmetic performs an arithmetic operation on d.  Dynamic values of basic
+// kinds are used like their corresponding Values.  Otherwise, the operation
+// is performed by d's value's method called name, e.g. `Add(T) T` or
+// `Add(T) (T, error)`.
+func (d Dynamic) arithmetic(ctx context.Context, op, name string, f func(ctx context.Context, left, right Value) (Value, error), v Value) (Value, error) {
+	if b, ok := d.basic(); ok {
+		return f(ctx, b, v)
+	}
+	result, ok, err := d.operator(PolicyFromContext(ctx), v, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, typeMismatch(op, d, v)
+	}
+	return ValueOf(result.Interface()), nil
+}
+
+// EvalAdd adds v to d.
+func (d Dynamic) EvalAdd(v Value) (Value, error) {
+	return d.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to d under ctx.
+func (d Dynamic) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "add", "Add", evalAdd, v)
+}
+
+// EvalSubtract subtracts v from d.
+func (d Dynamic) EvalSubtract(v Value) (Value, error) {
+	return d.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from d under ctx.
+func (d Dynamic) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "subtract", "Sub", evalSubtract, v)
+}
+
+// EvalMultiply multiplies d by v.
+func (d Dynamic) EvalMultiply(v Value) (Value, error) {
+	return d.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies d by v under ctx.
+func (d Dynamic) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "multiply", "Mul", evalMultiply, v)
+}
+
+// EvalDivide divides d by v.
+func (d Dynamic) EvalDivide(v Value) (Value, error) {
+	return d.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides d by v under ctx.
+func (d Dynamic) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "divide", "Div", evalDivide, v)
+}
+
+// EvalMod gets the remainder of dividing d by v.
+func (d Dynamic) EvalMod(v Value) (Value, error) {
+	return d.EvalModContext(context.Background(), v)
+}
+
+// EvalModContext gets the remainder of dividing d by v under ctx.
+func (d Dynamic) EvalModContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "mod", "Mod", evalMod, v)
+}
+
+// EvalPow raises d to the power of v.
+func (d Dynamic) EvalPow(v Value) (Value, error) {
+	return d.EvalPowContext(context.Background(), v)
+}
+
+// EvalPowContext raises d to the power of v under ctx.
+func (d Dynamic) EvalPowContext(ctx context.Context, v Value) (Value, error) {
+	return d.arithmetic(ctx, "raise", "Pow", evalPow, v)
+}
+
+// DynamicVar is a variable that holds Dynamic values of a single Go type.
+type DynamicVar struct {
+	typ   reflect.Type
+	value Dynamic
+}
+
+// NewDynamicVar creates a variable that holds values of the Go type t,
+// initialized to t's zero value.
+func NewDynamicVar(t reflect.Type) *DynamicVar {
+	return &DynamicVar{t, Dynamic(reflect.New(t).Elem())}
+}
+
+// Copy copies the DynamicVar.
+func (d *DynamicVar) Copy(transformations ...Mapper) Expr {
+	copied := d.value.Copy(transformations...)
+	if dv, ok := copied.(Dynamic); ok {
+		return ApplyMappers(&DynamicVar{d.typ, dv}, transformations...)
+	}
+	return copied
+}
+
+// Eval evaluates the DynamicVar.
+func (d *DynamicVar) Eval() (interface{}, error) {
+	return d.value.Eval()
+}
+
+// EvalValue evaluates the DynamicVar to its Dynamic value.
+func (d *DynamicVar) EvalValue() (Value, error) {
+	return d.value, nil
+}
+
+// Type gets the expression Type of the variable's values.
+func (d *DynamicVar) Type() Type {
+	return dynamictype{d.typ}
+}
+
+// Value gets the Dynamic value of the variable.
+func (d *DynamicVar) Value() Value {
+	return d.value
+}
+
+// SetValue sets the variable to v if v's Go value is assignable to the
+// variable's type.  Numbers are also accepted if the variable's type is
+// numeric and the number can be converted without losing precision.
+func (d *DynamicVar) SetValue(v Value) error {
+	var rv reflect.Value
+	if dv, ok := v.(Dynamic); ok {
+		rv = reflect.Value(dv)
+	} else {
+		rv = reflect.ValueOf(v.Interface())
+	}
+	target := reflect.New(d.typ).Elem()
+	switch {
+	case !rv.IsValid():
+		switch d.typ.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		default:
+			return conversionError(v, d.Type(), nil)
+		}
+	case rv.Type().AssignableTo(d.typ):
+		target.Set(rv)
+	default:
+		bt, ok := basicTypes[d.typ.Kind()]
+		if !ok {
+			return conversionError(v, d.Type(), nil)
+		}
+		c, err := Convert(unwrapDynamic(v), ValueOf(reflect.Zero(bt).Interface()).(TypedValueExpr).Type())
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(c.Interface()).Convert(d.typ))
+	}
+	d.value = Dynamic(target)
+	return nil
+}
+
+// String represents the variable by its value.
+func (d *DynamicVar) String() string {
+	return d.value.String()
+}
@@ -0,0 +1,292 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+type celsius float64
+
+type node struct {
+	Name     string
+	Tags     []string
+	Children map[string]*node
+	Parent   *node
+}
+
+type shallowNode node
+
+func TestDynamicCopy(t *testing.T) {
+	t.Parallel()
+	expr.SetCopyMode(reflect.TypeOf(node{}), expr.DeepCopy)
+	root := node{Name: "root", Tags: []string{"a"}}
+	root.Children = map[string]*node{"child": {Name: "child", Parent: &root}}
+	copied := expr.ValueOf(root).Copy().(expr.Value).Interface().(node)
+	copied.Tags[0] = "b"
+	copied.Children["child"].Name = "changed"
+	if root.Tags[0] != "a" || root.Children["child"].Name != "child" {
+		t.Errorf("deep copy shares data with the original: %+v", root)
+	}
+	child := copied.Children["child"]
+	if child.Parent == &root || child.Parent.Children["child"] != child {
+		t.Errorf("deep copy did not preserve the cycle between %v and its parent", child.Name)
+	}
+	shallow := shallowNode{Name: "root", Tags: []string{"a"}}
+	copiedShallow := expr.ValueOf(shallow).Copy().(expr.Value).Interface().(shallowNode)
+	copiedShallow.Tags[0] = "b"
+	copiedShallow.Name = "changed"
+	if shallow.Tags[0] != "b" || shallow.Name != "root" {
+		t.Errorf("expected shallow copy to share its tags but not its name: %+v", shallow)
+	}
+}
+
+type ring struct {
+	Name        string
+	Next, Alias *ring
+	Links       map[string]*ring
+}
+
+func TestDynamicCopyAliases(t *testing.T) {
+	t.Parallel()
+	expr.SetCopyMode(reflect.TypeOf((*ring)(nil)), expr.DeepCopy)
+	p := &ring{Name: "p"}
+	p.Next = p
+	q := &ring{Name: "q", Next: p, Alias: p, Links: map[string]*ring{"p": p}}
+	p.Alias = q
+	copied := expr.ValueOf(q).Copy().(expr.Value).Interface().(*ring)
+	c := copied.Next
+	if copied == q || c == p {
+		t.Fatalf("expected a deep copy of %v", q.Name)
+	}
+	if c.Next != c {
+		t.Errorf("deep copy did not preserve the cycle of %v", p.Name)
+	}
+	if copied.Alias != c || copied.Links["p"] != c || c.Alias != copied {
+		t.Errorf("deep copy did not preserve the aliases of %v", p.Name)
+	}
+}
+
+func TestDynamicVar(t *testing.T) {
+	t.Parallel()
+	v := expr.ValueOf(celsius(20)).(expr.TypedValueExpr).Type().Var()
+	if err := v.SetValue(expr.ValueOf(celsius(25))); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetValue(expr.Int(30)); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := v.Value().Interface().(celsius); !ok || c != 30 {
+		t.Errorf("expected celsius(30) but got %v (type: %T)", v.Value(), v.Value().Interface())
+	}
+	var ce *expr.ConversionError
+	if err := v.SetValue(expr.String("hot")); !errors.As(err, &ce) {
+		t.Errorf("expected *ConversionError but got %v", err)
+	}
+	nodes := expr.ValueOf(&node{}).(expr.TypedValueExpr).Type().Var()
+	if err := nodes.SetValue(expr.ValueOf(&node{Name: "n"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := nodes.SetValue(expr.ValueOf(node{})); err == nil {
+		t.Errorf("expected setting a *node variable to a node to fail")
+	}
+	name, err := expr.Attr{ValueExpr: nodes.(expr.ValueExpr), Name: "Name"}.EvalValue()
+	if err != nil || name != expr.String("n") {
+		t.Errorf("n.Name -> %v (err: %v)", name, err)
+	}
+}
+
+func TestDynamicNumbers(t *testing.T) {
+	t.Parallel()
+	temp := expr.ValueOf(celsius(21.5))
+	tcs := []expr.BoolExpr{
+		expr.Gt{temp, expr.Int(20)},
+		expr.Lt{expr.Int(20), temp},
+		expr.Eq{expr.Add{temp, expr.Float64(0.5)}, expr.Int(22)},
+		expr.Eq{expr.Mul{expr.Int(2), temp}, expr.Int(43)},
+		expr.Eq{expr.Sub{temp, temp}, expr.Int(0)},
+	}
+	for _, tc := range tcs {
+		ok, err := expr.EvalBoolContext(context.Background(), tc)
+		if err != nil {
+			t.Errorf("%v: %v", tc, err)
+			continue
+		}
+		if !ok {
+			t.Errorf("expected %v to be true", tc)
+		}
+	}
+	if _, err := (expr.Add{expr.ValueOf(node{}), expr.Int(1)}).EvalValue(); err == nil {
+		t.Errorf("expected adding to a struct to fail")
+	}
+}
+
+type money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m money) Add(o money) (money, error) {
+	if m.Currency != o.Currency {
+		return money{}, fmt.Errorf("cannot add %s to %s", o.Currency, m.Currency)
+	}
+	return money{m.Cents + o.Cents, m.Currency}, nil
+}
+
+func (m money) Mul(n int) money {
+	return money{m.Cents * int64(n), m.Currency}
+}
+
+func (m money) Cmp(o money) int {
+	return int(m.Cents - o.Cents)
+}
+
+func (m money) String() string {
+	return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+}
+
+type semver struct {
+	Major, Minor, Patch int
+}
+
+func (v *semver) Compare(o *semver) int {
+	for _, d := range [...]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+type color struct {
+	R, G, B uint8
+	Name    string
+}
+
+func (c color) Equal(o color) bool {
+	return c.R == o.R && c.G == o.G && c.B == o.B
+}
+
+func TestDynamicOperators(t *testing.T) {
+	t.Parallel()
+	price := expr.ValueOf(money{1999, "USD"})
+	tcs := []expr.BoolExpr{
+		expr.Eq{expr.Add{price, expr.ValueOf(money{1, "USD"})}, expr.ValueOf(money{2000, "USD"})},
+		expr.Gt{expr.Mul{price, expr.Int(3)}, expr.ValueOf(money{5000, "USD"})},
+		expr.Lt{expr.ValueOf(money{5, "USD"}), price},
+		expr.Eq{expr.Cast{price, expr.StringType}, expr.String("19.99 USD")},
+		expr.Lt{expr.ValueOf(semver{1, 2, 3}), expr.ValueOf(semver{1, 10, 0})},
+		expr.Ge{expr.ValueOf(&semver{2, 0, 0}), expr.ValueOf(semver{2, 0, 0})},
+		expr.Eq{expr.ValueOf(color{255, 0, 0, "red"}), expr.ValueOf(color{255, 0, 0, "rouge"})},
+		expr.Ne{expr.ValueOf(color{255, 0, 0, "red"}), expr.ValueOf(color{0, 0, 255, "blue"})},
+	}
+	for _, tc := range tcs {
+		ok, err := tc.EvalBool()
+		if err != nil {
+			t.Errorf("%v: %v", tc, err)
+			continue
+		}
+		if !ok {
+			t.Errorf("expected %v to be true", tc)
+		}
+	}
+	if _, err := (expr.Add{price, expr.ValueOf(money{1, "EUR"})}).EvalValue(); err == nil {
+		t.Errorf("expected adding EUR to USD to fail")
+	}
+	var tm *expr.TypeMismatchError
+	if _, err := (expr.Sub{price, price}).EvalValue(); !errors.As(err, &tm) {
+		t.Errorf("expected *TypeMismatchError but got %v", err)
+	}
+	var nc *expr.NotComparableError
+	if _, err := (expr.Lt{expr.ValueOf(color{}), expr.ValueOf(color{})}).EvalBool(); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+}
+
+type meter struct {
+	N     int
+	calls *int
+}
+
+func (m meter) Add(o meter) meter {
+	*m.calls++
+	return meter{m.N + o.N, m.calls}
+}
+
+func (m meter) Cmp(o meter) int {
+	*m.calls++
+	return m.N - o.N
+}
+
+func (m meter) Equal(o meter) bool {
+	*m.calls++
+	return m.N == o.N
+}
+
+func (m meter) String() string {
+	*m.calls++
+	return fmt.Sprintf("%dm", m.N)
+}
+
+func TestDynamicOperatorsPolicy(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	a, b := expr.ValueOf(meter{1, &calls}), expr.ValueOf(meter{2, &calls})
+	ctx := expr.WithPolicy(context.Background(), &expr.Policy{DefaultDeny: true, DisableMethods: true})
+	tcs := []expr.ValueExpr{
+		expr.Add{a, b},
+		expr.Lt{a, b},
+		expr.Eq{a, b},
+		expr.Ne{expr.ValueSet{a}, expr.ValueSet{b}},
+		expr.Cast{a, expr.StringType},
+	}
+	for _, tc := range tcs {
+		v, err := expr.EvalContext(ctx, tc)
+		var ae *expr.AccessError
+		if !errors.As(err, &ae) {
+			t.Errorf("%v: expected *AccessError but got %v (err: %v)", tc, v, err)
+		}
+		if calls != 0 {
+			t.Fatalf("%v: denied method was called", tc)
+		}
+	}
+	// Representing the values doesn't call their String methods either:
+	_, err := expr.EvalContext(ctx, expr.Add{a, expr.String("x")})
+	if s := fmt.Sprint(a, expr.Add{a, b}, err); calls != 0 {
+		t.Fatalf("String method was called to represent %s", s)
+	}
+	for _, tc := range tcs {
+		if _, err := expr.EvalContext(context.Background(), tc); err != nil {
+			t.Errorf("%v: %v", tc, err)
+		}
+	}
+	if v, err := expr.EvalContext(context.Background(), expr.Cast{a, expr.StringType}); err != nil || v != expr.String("1m") {
+		t.Errorf("expected the cast to use the String method but got %v (err: %v)", v, err)
+	}
+}
+
+func TestDynamicString(t *testing.T) {
+	t.Parallel()
+	type plain struct {
+		A int
+		B []string
+		C map[string]float64
+		D *int
+		E interface{}
+	}
+	n := 1
+	for _, x := range []interface{}{
+		plain{1, []string{"x", "y"}, map[string]float64{"b": 2.5, "a": 1}, &n, true},
+		&plain{E: plain{}},
+		[]int{1, 2},
+		map[int]string{2: "b", 1: "a"},
+	} {
+		if s, expect := fmt.Sprint(expr.ValueOf(x)), fmt.Sprint(x); s != expect {
+			t.Errorf("expected %s but got %s", expect, s)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package expr
 
 import (
-	"github.com/skillian/errors"
+	"context"
 )
 
 type unaryBool [1]BoolExpr
@@ -24,13 +24,24 @@ func (n Not) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (n Not) EvalBool() (bool, error) {
-	op, err := n.Operand().Eval()
+	return EvalBoolContext(context.Background(), n)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (n Not) EvalBoolContext(ctx context.Context) (bool, error) {
+	op, err := evalValueContext(ctx, n[0])
 	if err != nil {
-		return false, errors.ErrorfWithCause(err, "failed to evaluate Not expression")
+		return false, err
 	}
 	return !Truthy(op), nil
 }
 
+// EvalValue evaluates the expression.
+func (n Not) EvalValue() (Value, error) {
+	result, err := n.EvalBool()
+	return Bool(result), err
+}
+
 // Eval the expression.
 func (n Not) Eval() (interface{}, error) {
 	return n.EvalBool()
@@ -75,11 +86,12 @@ func (eq Eq) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a boolean result.
 func (eq Eq) EvalBool() (bool, error) {
-	result, err := Cmp(eq.Left(), eq.Right())
-	if err != nil {
-		return false, err
-	}
-	return result == 0, nil
+	return EvalBoolContext(context.Background(), eq)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (eq Eq) EvalBoolContext(ctx context.Context) (bool, error) {
+	return equalContext(ctx, eq.Left(), eq.Right())
 }
 
 // EvalValue evaluates the expression.
@@ -120,11 +132,16 @@ func (ne Ne) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (ne Ne) EvalBool() (bool, error) {
-	result, err := Cmp(ne.Left(), ne.Right())
+	return EvalBoolContext(context.Background(), ne)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (ne Ne) EvalBoolContext(ctx context.Context) (bool, error) {
+	equal, err := equalContext(ctx, ne.Left(), ne.Right())
 	if err != nil {
 		return false, err
 	}
-	return result != 0, nil
+	return !equal, nil
 }
 
 // EvalValue evaluates the expression.
@@ -165,7 +182,12 @@ func (gt Gt) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (gt Gt) EvalBool() (bool, error) {
-	result, err := Cmp(gt.Left(), gt.Right())
+	return EvalBoolContext(context.Background(), gt)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (gt Gt) EvalBoolContext(ctx context.Context) (bool, error) {
+	result, err := cmpContext(ctx, gt.Left(), gt.Right())
 	if err != nil {
 		return false, err
 	}
@@ -210,7 +232,12 @@ func (ge Ge) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (ge Ge) EvalBool() (bool, error) {
-	result, err := Cmp(ge.Left(), ge.Right())
+	return EvalBoolContext(context.Background(), ge)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (ge Ge) EvalBoolContext(ctx context.Context) (bool, error) {
+	result, err := cmpContext(ctx, ge.Left(), ge.Right())
 	if err != nil {
 		return false, err
 	}
@@ -255,7 +282,12 @@ func (lt Lt) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (lt Lt) EvalBool() (bool, error) {
-	result, err := Cmp(lt.Left(), lt.Right())
+	return EvalBoolContext(context.Background(), lt)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (lt Lt) EvalBoolContext(ctx context.Context) (bool, error) {
+	result, err := cmpContext(ctx, lt.Left(), lt.Right())
 	if err != nil {
 		return false, err
 	}
@@ -300,7 +332,12 @@ func (le Le) Copy(transformations ...Mapper) Expr {
 
 // EvalBool evaluates the expression to a bool result.
 func (le Le) EvalBool() (bool, error) {
-	result, err := Cmp(le.Left(), le.Right())
+	return EvalBoolContext(context.Background(), le)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (le Le) EvalBoolContext(ctx context.Context) (bool, error) {
+	result, err := cmpContext(ctx, le.Left(), le.Right())
 	if err != nil {
 		return false, err
 	}
@@ -1,13 +1,14 @@
 package expr
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/skillian/errors"
 	"github.com/skillian/logging"
 )
 
 var (
-	errNilPtr error = errors.ErrorMessage{Fmt: "nil pointer"}
-
 	logger = logging.GetLogger("github.com/skillian/expr")
 )
 
@@ -35,3 +36,207 @@ func TypeErrorFromExpectedAndActual(expected, actual interface{}) TypeError {
 func (te TypeError) Error() string {
 	return errors.Error(te).Error()
 }
+
+// ExprError holds the details shared by the errors that occur while
+// evaluating an expression.  The evaluation fills in Node and Path when the
+// error passes through it.
+type ExprError struct {
+	// Node is the expression whose evaluation failed.
+	Node Expr
+
+	// Operands are the values that Node was evaluated with.
+	Operands []Value
+
+	// Path holds the expressions from the root of the evaluation down to
+	// and including Node.
+	Path []Expr
+}
+
+// exprError is implemented by the structured errors in this package so that
+// their ExprError can be filled in during evaluation.
+type exprError interface {
+	error
+	exprError() *ExprError
+}
+
+func (e *ExprError) exprError() *ExprError {
+	return e
+}
+
+// annotate fills in the node and path of err if it is one of the structured
+// errors and they have not been set yet.
+func annotate(err error, node Expr, path []Expr) {
+	ee, ok := err.(exprError)
+	if !ok {
+		return
+	}
+	d := ee.exprError()
+	if d.Node == nil {
+		d.Node = node
+	}
+	if d.Path == nil && len(path) > 0 {
+		d.Path = append([]Expr(nil), path...)
+	}
+}
+
+// where describes where the error occurred if the node is known.
+func (e *ExprError) where() string {
+	if e.Node == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (in %v)", StringifyExpr(e.Node, false))
+}
+
+// TypeMismatchError is returned when an operation is applied to operands
+// whose types it does not support.
+type TypeMismatchError struct {
+	ExprError
+
+	// Op is the name of the operation (e.g. "add").
+	Op string
+}
+
+func typeMismatch(op string, operands ...Value) *TypeMismatchError {
+	return &TypeMismatchError{ExprError{Operands: operands}, op}
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf(
+		"cannot %s %s%s", e.Op, describeOperands(e.Operands), e.where())
+}
+
+// DivisionByZeroError is returned when a value is divided by zero.
+type DivisionByZeroError struct {
+	ExprError
+}
+
+func divisionByZero(operands ...Value) *DivisionByZeroError {
+	return &DivisionByZeroError{ExprError{Operands: operands}}
+}
+
+func (e *DivisionByZeroError) Error() string {
+	return fmt.Sprintf(
+		"division by zero: %s%s", describeOperands(e.Operands), e.where())
+}
+
+// UnknownAttributeError is returned when a value has no attribute with the
+// given name.
+type UnknownAttributeError struct {
+	ExprError
+
+	// Name of the attribute.
+	Name string
+}
+
+func unknownAttribute(value Value, name string) *UnknownAttributeError {
+	return &UnknownAttributeError{ExprError{Operands: []Value{value}}, name}
+}
+
+func (e *UnknownAttributeError) Error() string {
+	return fmt.Sprintf(
+		"%s has no attribute %q%s",
+		describeOperands(e.Operands), e.Name, e.where())
+}
+
+// NotComparableError is returned when two values cannot be compared.
+type NotComparableError struct {
+	ExprError
+
+	// Err is the reason the values could not be compared, if any.
+	Err error
+}
+
+func notComparable(left, right Value, err error) *NotComparableError {
+	return &NotComparableError{ExprError{Operands: []Value{left, right}}, err}
+}
+
+func (e *NotComparableError) Error() string {
+	msg := fmt.Sprintf(
+		"cannot compare %s%s", describeOperands(e.Operands), e.where())
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap gets the reason the values could not be compared.
+func (e *NotComparableError) Unwrap() error {
+	return e.Err
+}
+
+// OverflowError is returned when a result cannot be represented by its
+// type.
+type OverflowError struct {
+	ExprError
+
+	// Type is the type that could not represent the result.
+	Type Type
+}
+
+func overflow(t Type, operands ...Value) *OverflowError {
+	return &OverflowError{ExprError{Operands: operands}, t}
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf(
+		"%s overflows %s%s",
+		describeOperands(e.Operands), typeName(e.Type), e.where())
+}
+
+// ConversionError is returned when a value cannot be converted into a Type.
+type ConversionError struct {
+	ExprError
+
+	// Type that the value could not be converted into.
+	Type Type
+
+	// Err is the reason why the conversion failed, if any.
+	Err error
+}
+
+func conversionError(v Value, t Type, err error) *ConversionError {
+	return &ConversionError{ExprError{Operands: []Value{v}}, t, err}
+}
+
+func (e *ConversionError) Error() string {
+	reason := ""
+	if e.Err != nil {
+		reason = ": " + e.Err.Error()
+	}
+	return fmt.Sprintf(
+		"cannot convert %s to %s%s%s",
+		describeOperands(e.Operands), typeName(e.Type), reason, e.where())
+}
+
+// Unwrap gets the reason why the conversion failed.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// NilVarError is returned when a variable without a value is evaluated.
+type NilVarError struct {
+	ExprError
+}
+
+func (e *NilVarError) Error() string {
+	return "nil variable" + e.where()
+}
+
+func describeOperands(operands []Value) string {
+	descs := make([]string, len(operands))
+	for i, operand := range operands {
+		descs[i] = fmt.Sprintf("%v (type: %T)", operand, operand)
+	}
+	return strings.Join(descs, " and ")
+}
+
+// typeName gets a readable name of an expression Type.
+func typeName(t Type) string {
+	switch t := t.(type) {
+	case nil:
+		return "<nil>"
+	case dynamictype:
+		return t.Type.String()
+	}
+	return fmt.Sprintf("%T", t.Zero())
+}
@@ -0,0 +1,69 @@
+package expr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestExprErrors(t *testing.T) {
+	t.Parallel()
+	mismatch := expr.Add{expr.Int(1), expr.String("a")}
+	incomparable := expr.Lt{expr.Int(1), expr.String("a")}
+	unknown := expr.Attr{ValueExpr: expr.ValueOf(struct{ Name string }{"Ryan"}), Name: "Age"}
+	nilvar := new(expr.RationalVar)
+	tcs := []struct {
+		name string
+		expr.Expr
+		node   expr.Expr
+		path   int
+		target interface{}
+	}{
+		{"type mismatch", expr.Mul{expr.Int(2), mismatch}, mismatch, 2, new(*expr.TypeMismatchError)},
+		{"not comparable", expr.All{expr.Eq{expr.Int(1), expr.Int(1)}, incomparable}, incomparable, 2, new(*expr.NotComparableError)},
+		{"unknown attribute", expr.Eq{unknown, expr.String("Ryan")}, unknown, 2, new(*expr.UnknownAttributeError)},
+		{"nil var", expr.Add{expr.Int(1), nilvar}, nilvar, 2, new(*expr.NilVarError)},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.Expr.Eval()
+			if err == nil {
+				t.Fatalf("expected %v to fail", tc.Expr)
+			}
+			if !errors.As(err, tc.target) {
+				t.Fatalf("expected %T but got %v (type: %T)", tc.target, err, err)
+			}
+			t.Log(err)
+			ee := exprErrorOf(t, err)
+			if ee.Node != tc.node {
+				t.Errorf("expected node %v but got %v", tc.node, ee.Node)
+			}
+			if len(ee.Path) != tc.path {
+				t.Errorf("expected path of length %d but got %v", tc.path, ee.Path)
+			}
+		})
+	}
+}
+
+func exprErrorOf(t *testing.T, err error) *expr.ExprError {
+	t.Helper()
+	var (
+		tm *expr.TypeMismatchError
+		nc *expr.NotComparableError
+		ua *expr.UnknownAttributeError
+		nv *expr.NilVarError
+	)
+	switch {
+	case errors.As(err, &tm):
+		return &tm.ExprError
+	case errors.As(err, &nc):
+		return &nc.ExprError
+	case errors.As(err, &ua):
+		return &ua.ExprError
+	case errors.As(err, &nv):
+		return &nv.ExprError
+	}
+	t.Fatalf("%v is not a structured error", err)
+	return nil
+}
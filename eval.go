@@ -1,21 +1,14 @@
 package expr
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/skillian/errors"
 )
 
 // EvalValue evaluates the full expression into a Value
 func EvalValue(e ValueExpr) (Value, error) {
-	result := Simplify(e, EvaluateVariable)
-	value, ok := result.(Value)
-	if ok {
-		return value, nil
-	}
-	return nil, errors.Errorf(
-		"Evaluating expression %v did not produce a value (result: %v)",
-		e, result)
+	return EvalContext(context.Background(), e)
 }
 
 // EvalValueInto evaluates the given expressions and puts their results into the
@@ -36,9 +29,11 @@ func EvalValueInto(exprs []ValueExpr, values []Value) (err error) {
 // Simplify performs some compile-time-like simlifications on a copy of its
 // expression and returns it.  Arithmetic and logical expressions on constants
 // will be evaluated but not operations on Vars (unless explicitly included
-// in the additional simplifications).
+// in the additional simplifications).  Identities such as x * 1 on Float64s
+// and Rationals and Not{Not{x}} are rewritten into their operands before the
+// additional simplifications are applied.
 func Simplify(e Expr, simplifications ...Mapper) Expr {
-	builtins := []Mapper{}
+	builtins := []Mapper{simplifyMapper}
 	simplifications = append(builtins, simplifications...)
 	return e.Copy(simplifications...)
 }
@@ -69,12 +64,10 @@ func SimplifyAllInPlace(exprs []Expr, simplifications ...Mapper) {
 // Value and return that.  This is useful when you intend to evaluate the
 // expression locally.
 func EvaluateVariable(e Expr) Expr {
-	logger.Error1("EvaluatingVariable: %v", e)
 	vari, ok := e.(Var)
 	if ok {
 		value, ok := vari.Value().(Expr)
 		if ok {
-			fmt.Printf("EvaluateVariable %v -> %v", e, value)
 			return value
 		}
 	}
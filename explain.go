@@ -0,0 +1,171 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Trace is an explanation of the evaluation of an expression.
+type Trace struct {
+	// Root is the node of the expression that was explained.
+	Root *TraceNode
+}
+
+// TraceNode records the evaluation of a single expression.
+type TraceNode struct {
+	// Expr is the evaluated expression.
+	Expr Expr
+
+	// Value is the expression's result.  It is nil if the expression
+	// failed or was skipped.
+	Value Value
+
+	// Err is the error that the evaluation of the expression failed with.
+	Err error
+
+	// Decisive is true if this is the operand of an All or Any that
+	// decided the result without evaluating the rest of the operands.
+	Decisive bool
+
+	// Skipped is true if the expression wasn't evaluated because an
+	// earlier operand of its All or Any decided the result.
+	Skipped bool
+
+	// Operands are the nodes of the expression's operands in the order
+	// they were evaluated.
+	Operands []*TraceNode
+}
+
+// Explain evaluates e and records every expression evaluated along the way.
+// The Trace is returned even if the evaluation fails so the failure can be
+// explained too.
+func Explain(e BoolExpr) (Trace, error) {
+	return ExplainContext(context.Background(), e)
+}
+
+// ExplainContext evaluates e under ctx like EvalBoolContext and explains it
+// like Explain.
+func ExplainContext(ctx context.Context, e BoolExpr) (Trace, error) {
+	st := &evalState{trace: &tracer{}}
+	st.Limits, _ = LimitsFromContext(ctx)
+	ctx = context.WithValue(ctx, evalStateKey{}, st)
+	_, err := evalBoolContext(ctx, e)
+	return Trace{Root: st.trace.root}, err
+}
+
+// String renders the trace as an indented tree with a line for each
+// expression.
+func (t Trace) String() string {
+	var sb strings.Builder
+	if t.Root != nil {
+		t.Root.writeTo(&sb, 0)
+	}
+	return sb.String()
+}
+
+// MarshalJSON renders the trace as a tree of JSON objects.
+func (t Trace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Root)
+}
+
+// writeTo writes the node and its operands into sb.
+func (n *TraceNode) writeTo(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(StringifyExpr(n.Expr, false))
+	switch {
+	case n.Skipped:
+		sb.WriteString(" (skipped)")
+	case n.Err != nil:
+		sb.WriteString(" failed: ")
+		sb.WriteString(n.Err.Error())
+	case !IsConst(n.Expr):
+		sb.WriteString(" -> ")
+		sb.WriteString(StringifyExpr(n.Value, false))
+	}
+	if n.Decisive {
+		sb.WriteString(" (decisive)")
+	}
+	sb.WriteByte('\n')
+	for _, o := range n.Operands {
+		o.writeTo(sb, depth+1)
+	}
+}
+
+// MarshalJSON renders the node and its operands as a JSON object.
+func (n *TraceNode) MarshalJSON() ([]byte, error) {
+	type jsonNode struct {
+		Expr     string       `json:"expr"`
+		Value    string       `json:"value,omitempty"`
+		Error    string       `json:"error,omitempty"`
+		Decisive bool         `json:"decisive,omitempty"`
+		Skipped  bool         `json:"skipped,omitempty"`
+		Operands []*TraceNode `json:"operands,omitempty"`
+	}
+	jn := jsonNode{
+		Expr:     StringifyExpr(n.Expr, false),
+		Decisive: n.Decisive,
+		Skipped:  n.Skipped,
+		Operands: n.Operands,
+	}
+	if n.Value != nil {
+		jn.Value = StringifyExpr(n.Value, false)
+	}
+	if n.Err != nil {
+		jn.Error = n.Err.Error()
+	}
+	return json.Marshal(jn)
+}
+
+// tracer builds a Trace while an expression is evaluated.
+type tracer struct {
+	root  *TraceNode
+	stack []*TraceNode
+}
+
+// enter adds a node for e as an operand of the expression currently being
+// evaluated.
+func (t *tracer) enter(e Expr) {
+	n := &TraceNode{Expr: e}
+	if len(t.stack) == 0 {
+		t.root = n
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Operands = append(parent.Operands, n)
+	}
+	t.stack = append(t.stack, n)
+}
+
+// leave records the result of the expression currently being evaluated.
+// If the expression is an All or Any that stopped early, the operand that
+// decided the result is marked and the rest of the operands are added as
+// skipped.
+func (t *tracer) leave(v Value, err error) {
+	n := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	if err != nil {
+		n.Err = err
+		return
+	}
+	n.Value = v
+	var operands []BoolExpr
+	switch e := n.Expr.(type) {
+	case All:
+		operands = e
+	case Any:
+		operands = e
+	default:
+		return
+	}
+	if len(n.Operands) == 0 || len(n.Operands) > len(operands) {
+		return
+	}
+	last := n.Operands[len(n.Operands)-1]
+	_, all := n.Expr.(All)
+	if last.Err == nil && Truthy(last.Value) != all {
+		last.Decisive = true
+	}
+	for _, operand := range operands[len(n.Operands):] {
+		n.Operands = append(n.Operands, &TraceNode{Expr: operand, Skipped: true})
+	}
+}
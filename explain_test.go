@@ -0,0 +1,89 @@
+package expr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	order := expr.ValueOf(struct {
+		Total int
+		Items int
+	}{40, 3})
+	total := expr.Attr{ValueExpr: order, Name: "Total"}
+	items := expr.Attr{ValueExpr: order, Name: "Items"}
+	e := expr.All{
+		expr.Gt{items, expr.Int(1)},
+		expr.Ge{expr.Sub{total, expr.Int(10)}, expr.Int(50)},
+		expr.Lt{total, expr.Int(100)},
+	}
+	trace, err := expr.Explain(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `[({40 3}.Items) > 1 (({40 3}.Total) - 10) >= 50 ({40 3}.Total) < 100] -> false
+  ({40 3}.Items) > 1 -> true
+    {40 3}.Items -> 3
+      {40 3}
+    1
+  (({40 3}.Total) - 10) >= 50 -> false (decisive)
+    ({40 3}.Total) - 10 -> 30.0000000000
+      {40 3}.Total -> 40
+        {40 3}
+      10
+    50
+  ({40 3}.Total) < 100 (skipped)
+`
+	if s := trace.String(); s != expect {
+		t.Errorf("unexpected explanation:\n%s", s)
+	}
+	var root struct {
+		Value    string
+		Operands []struct {
+			Decisive bool
+			Skipped  bool
+		}
+	}
+	b, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Value != "false" || len(root.Operands) != 3 ||
+		root.Operands[0].Decisive || !root.Operands[1].Decisive || !root.Operands[2].Skipped {
+		t.Errorf("unexpected JSON explanation: %s", b)
+	}
+	any := expr.Any{expr.Eq{items, expr.Int(2)}, expr.Eq{items, expr.Int(3)}, expr.Eq{items, expr.Int(4)}}
+	if trace, err = expr.Explain(any); err != nil {
+		t.Fatal(err)
+	}
+	if ops := trace.Root.Operands; len(ops) != 3 || ops[0].Decisive || !ops[1].Decisive || !ops[2].Skipped {
+		t.Errorf("unexpected explanation:\n%v", trace)
+	}
+}
+
+func TestExplainError(t *testing.T) {
+	t.Parallel()
+	e := expr.Any{
+		expr.Eq{expr.Int(1), expr.Int(2)},
+		expr.Gt{expr.Div{expr.Int(1), expr.Int(0)}, expr.Int(0)},
+	}
+	trace, err := expr.Explain(e)
+	var dz *expr.DivisionByZeroError
+	if !errors.As(err, &dz) {
+		t.Fatalf("expected *DivisionByZeroError but got %v", err)
+	}
+	failed := trace.Root.Operands[1].Operands[0]
+	if !errors.As(failed.Err, &dz) || failed.Value != nil {
+		t.Errorf("expected the division to fail but got %v", failed.Value)
+	}
+	if trace.Root.Err != err || trace.Root.Operands[0].Value != expr.False {
+		t.Errorf("unexpected explanation:\n%v", trace)
+	}
+}
@@ -1,5 +1,11 @@
 package expr
 
+import (
+	"reflect"
+
+	"github.com/skillian/errors"
+)
+
 // Expr represents any expression.
 type Expr interface {
 	// Copy creates a copy of the expression and its children.
@@ -59,10 +65,78 @@ func Pipeline(mappers ...Mapper) Mapper {
 // IsTerminal returns true if the expression is a terminal expression in the
 // tree.
 func IsTerminal(e Expr) bool {
-	switch e := e.(type) {
+	switch e.(type) {
 	case Value, Var:
 		return true
-		_ = e
 	}
 	return false
 }
+
+// Operands gets the operands of e.  Terminal expressions have no operands.
+func Operands(e Expr) []Expr {
+	switch e := e.(type) {
+	case Multary:
+		return e.Operands()
+	case Binary:
+		return []Expr{e.Left(), e.Right()}
+	case Unary:
+		return []Expr{e.Operand()}
+	case Attr:
+		return []Expr{e.ValueExpr}
+	case Set:
+		operands := make([]Expr, len(e))
+		for i, operand := range e {
+			operands[i] = operand
+		}
+		return operands
+	}
+	return nil
+}
+
+// WithOperands creates a copy of e with its operands replaced.  The operands
+// must be in the same order as they are returned by Operands and each must
+// be the kind of expression that e's operand can be (e.g. a BoolExpr for the
+// operand of a Not).
+func WithOperands(e Expr, operands []Expr) (Expr, error) {
+	if n := len(Operands(e)); n != len(operands) {
+		return nil, errors.Errorf(
+			"%v has %d operands, not %d", e, n, len(operands))
+	}
+	if len(operands) == 0 {
+		return e, nil
+	}
+	v := reflect.ValueOf(e)
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Array:
+		c.Set(v)
+	case reflect.Slice:
+		c.Set(reflect.MakeSlice(v.Type(), len(operands), len(operands)))
+	case reflect.Struct:
+		c.Set(v)
+		if f := c.Field(0); len(operands) == 1 && f.Kind() == reflect.Interface && f.CanSet() {
+			return setOperand(c, f, operands[0])
+		}
+		fallthrough
+	default:
+		return nil, errors.Errorf("cannot replace the operands of %v (type: %T)", e, e)
+	}
+	for i, operand := range operands {
+		if _, err := setOperand(c, c.Index(i), operand); err != nil {
+			return nil, err
+		}
+	}
+	return c.Interface().(Expr), nil
+}
+
+// setOperand sets the operand at dst within the expression e and returns e.
+func setOperand(e, dst reflect.Value, operand Expr) (Expr, error) {
+	ov := reflect.ValueOf(operand)
+	if !ov.IsValid() || !ov.Type().AssignableTo(dst.Type()) {
+		return nil, errors.Errorf(
+			"%v (type: %T) cannot be an operand of %v",
+			operand, operand, e.Type())
+	}
+	dst.Set(ov)
+	return e.Interface().(Expr), nil
+}
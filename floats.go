@@ -1,9 +1,8 @@
 package expr
 
 import (
+	"context"
 	"math/big"
-
-	"github.com/skillian/errors"
 )
 
 // Float32 wraps a Go float32 value.
@@ -31,9 +30,9 @@ func (f Float32) Copy(transformations ...Mapper) Expr {
 // is returned.
 func (f Float32) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(f, n), nil
+		return cmpNumbers(f, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", f, v)
+	return 0, notComparable(f, v, nil)
 }
 
 // Eval the expression.
@@ -63,11 +62,12 @@ func (f *Float32) Value() Value {
 
 // SetValue of the expression.
 func (f *Float32) SetValue(v Value) error {
-	if v, ok := v.(Float32); ok {
-		*f = v
-		return nil
+	c, err := Convert(v, Float32Type)
+	if err != nil {
+		return err
 	}
-	return TypeErrorFromExpectedAndActual(f, v)
+	*f = c.(Float32)
+	return nil
 }
 
 // Rat sets the *big.Rat from this Float32.
@@ -75,6 +75,85 @@ func (f Float32) Rat(r *big.Rat) {
 	r.SetFloat64(float64(float32(f)))
 }
 
+// EvalAdd adds v to f.  The result is a Decimal if v is a Decimal, a
+// complex number if v is complex, a Float32 if v is a Float32 and a Float64
+// otherwise.
+func (f Float32) EvalAdd(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return decimalArithmeticHelper(f, v, (*big.Rat).Add)
+	case Complex64, Complex128:
+		return complexArithmeticHelper(context.Background(), opAdd, f, v)
+	case Float32:
+		return f + v, nil
+	case Number:
+		return Float64(f) + Float64(floatOf(v)), nil
+	}
+	return nil, typeMismatch("add", f, v)
+}
+
+// EvalSubtract subtracts v from f.  The result is a Decimal if v is a
+// Decimal, a complex number if v is complex, a Float32 if v is a Float32
+// and a Float64 otherwise.
+func (f Float32) EvalSubtract(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return decimalArithmeticHelper(f, v, (*big.Rat).Sub)
+	case Complex64, Complex128:
+		return complexArithmeticHelper(context.Background(), opSub, f, v)
+	case Float32:
+		return f - v, nil
+	case Number:
+		return Float64(f) - Float64(floatOf(v)), nil
+	}
+	return nil, typeMismatch("subtract", f, v)
+}
+
+// EvalMultiply multiplies f by v.  The result is a Decimal if v is a
+// Decimal, a complex number if v is complex, a Float32 if v is a Float32
+// and a Float64 otherwise.
+func (f Float32) EvalMultiply(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return decimalArithmeticHelper(f, v, (*big.Rat).Mul)
+	case Complex64, Complex128:
+		return complexArithmeticHelper(context.Background(), opMul, f, v)
+	case Float32:
+		return f * v, nil
+	case Number:
+		return Float64(f) * Float64(floatOf(v)), nil
+	}
+	return nil, typeMismatch("multiply", f, v)
+}
+
+// EvalDivide divides f by v.
+func (f Float32) EvalDivide(v Value) (Value, error) {
+	return f.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides f by v under ctx.  The result is a Decimal if v
+// is a Decimal, a complex number if v is complex, a Float32 if v is a
+// Float32 and a Float64 otherwise.
+func (f Float32) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(ctx, opQuo, f, v)
+	}
+	n, ok := v.(Number)
+	if !ok {
+		return nil, typeMismatch("divide", f, v)
+	}
+	if err := checkFloatDivisor(ctx, f, n); err != nil {
+		return nil, err
+	}
+	if d, ok := v.(Decimal); ok && d.int().Sign() != 0 {
+		return decimalArithmeticHelper(f, d, (*big.Rat).Quo)
+	}
+	if v, ok := v.(Float32); ok {
+		return f / v, nil
+	}
+	return Float64(f) / Float64(floatOf(n)), nil
+}
+
 // Float64 wraps a Go float64 value.
 type Float64 float64
 
@@ -100,19 +179,19 @@ func (f Float64) Copy(transformations ...Mapper) Expr {
 // comparison fails.
 func (f Float64) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(f, n), nil
+		return cmpNumbers(f, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", f, v)
+	return 0, notComparable(f, v, nil)
 }
 
 // Eval the expression.
 func (f Float64) Eval() (interface{}, error) {
-	return f.Value(), nil
+	return f.Interface(), nil
 }
 
 // Interface of the expression.
 func (f Float64) Interface() interface{} {
-	return float32(f)
+	return float64(f)
 }
 
 // EvalValue evaluates the expression to a a value.
@@ -132,14 +211,11 @@ func (f *Float64) Value() Value {
 
 // SetValue of the expression.
 func (f *Float64) SetValue(v Value) error {
-	switch v := v.(type) {
-	case Float64:
-		*f = v
-	case Float32:
-		*f = Float64(float64(float32(v)))
-	default:
-		return TypeErrorFromExpectedAndActual(f, v)
+	c, err := Convert(v, Float64Type)
+	if err != nil {
+		return err
 	}
+	*f = c.(Float64)
 	return nil
 }
 
@@ -147,3 +223,91 @@ func (f *Float64) SetValue(v Value) error {
 func (f Float64) Rat(r *big.Rat) {
 	r.SetFloat64(float64(f))
 }
+
+// EvalAdd adds v to f.
+func (f Float64) EvalAdd(v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(context.Background(), opAdd, f, v)
+	}
+	if d, ok := v.(Decimal); ok {
+		return decimalArithmeticHelper(f, d, (*big.Rat).Add)
+	}
+	if n, ok := v.(Number); ok {
+		return f + Float64(floatOf(n)), nil
+	}
+	return nil, typeMismatch("add", f, v)
+}
+
+// EvalSubtract subtracts v from f.
+func (f Float64) EvalSubtract(v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(context.Background(), opSub, f, v)
+	}
+	if d, ok := v.(Decimal); ok {
+		return decimalArithmeticHelper(f, d, (*big.Rat).Sub)
+	}
+	if n, ok := v.(Number); ok {
+		return f - Float64(floatOf(n)), nil
+	}
+	return nil, typeMismatch("subtract", f, v)
+}
+
+// EvalMultiply multiplies f by v.
+func (f Float64) EvalMultiply(v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(context.Background(), opMul, f, v)
+	}
+	if d, ok := v.(Decimal); ok {
+		return decimalArithmeticHelper(f, d, (*big.Rat).Mul)
+	}
+	if n, ok := v.(Number); ok {
+		return f * Float64(floatOf(n)), nil
+	}
+	return nil, typeMismatch("multiply", f, v)
+}
+
+// EvalDivide divides f by v.
+func (f Float64) EvalDivide(v Value) (Value, error) {
+	return f.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides f by v under ctx.
+func (f Float64) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(ctx, opQuo, f, v)
+	}
+	n, ok := v.(Number)
+	if !ok {
+		return nil, typeMismatch("divide", f, v)
+	}
+	if err := checkFloatDivisor(ctx, f, n); err != nil {
+		return nil, err
+	}
+	if d, ok := v.(Decimal); ok && d.int().Sign() != 0 {
+		return decimalArithmeticHelper(f, d, (*big.Rat).Quo)
+	}
+	return f / Float64(floatOf(n)), nil
+}
+
+// floatOf gets the value of n as a float64.
+func floatOf(n Number) float64 {
+	switch n := n.(type) {
+	case Float32:
+		return float64(n)
+	case Float64:
+		return float64(n)
+	}
+	var r big.Rat
+	n.Rat(&r)
+	f, _ := r.Float64()
+	return f
+}
+
+// checkFloatDivisor checks that the divisor of a floating point division is
+// not zero unless the context's DivisionMode is DivideByZeroIEEE.
+func checkFloatDivisor(ctx context.Context, dividend Value, divisor Number) error {
+	if floatOf(divisor) != 0 || DivisionModeFromContext(ctx) == DivideByZeroIEEE {
+		return nil
+	}
+	return divisionByZero(dividend, divisor)
+}
@@ -0,0 +1,128 @@
+package expr
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/skillian/errors"
+)
+
+var valueInterfaceType = reflect.TypeOf((*Value)(nil)).Elem()
+
+// Lit creates a literal Value from a Go value.  It is a typed shorthand for
+// ValueOf.
+func Lit[T any](v T) Value {
+	return ValueOf(v)
+}
+
+// EvalAs evaluates e and gets its result as a T.  T can be a Value type
+// (e.g. Int) or a Go type (e.g. int).  The result is converted into T with
+// Convert if it has a different type.
+func EvalAs[T any](e ValueExpr) (T, error) {
+	return EvalAsContext[T](context.Background(), e)
+}
+
+// EvalAsContext evaluates e under ctx and gets its result as a T like
+// EvalAs.
+func EvalAsContext[T any](ctx context.Context, e ValueExpr) (T, error) {
+	v, err := EvalContext(ctx, e)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ValueAs[T](v)
+}
+
+// ValueAs gets v as a T.  See EvalAs.
+func ValueAs[T any](v Value) (T, error) {
+	if t, ok := v.Interface().(T); ok {
+		return t, nil
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	var zero T
+	t, ok := typeOf[T]()
+	if !ok {
+		return zero, TypeErrorFromExpectedAndActual(zero, v)
+	}
+	c, err := Convert(v, t)
+	if err != nil {
+		return zero, err
+	}
+	if t, ok := c.Interface().(T); ok {
+		return t, nil
+	}
+	if t, ok := c.(T); ok {
+		return t, nil
+	}
+	return zero, TypeErrorFromExpectedAndActual(zero, c)
+}
+
+// typeOf gets the expression Type of values of the Go type T.  T can be a
+// Value type or a Go type whose values are mapped to Values by ValueOf.
+func typeOf[T any]() (Type, bool) {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	var v interface{}
+	switch {
+	case rt.Kind() == reflect.Interface:
+		return nil, false
+	case rt.Kind() == reflect.Ptr && rt.Implements(valueInterfaceType):
+		v = reflect.New(rt.Elem()).Interface()
+	case rt.Implements(valueInterfaceType):
+		var zero T
+		v = zero
+	default:
+		var zero T
+		v = ValueOf(zero)
+	}
+	tv, ok := v.(TypedValueExpr)
+	if !ok {
+		return nil, false
+	}
+	return tv.Type(), true
+}
+
+// TypedVar is a variable that holds values of the Go type T.
+type TypedVar[T any] struct {
+	VarExpr
+}
+
+// NewVar creates a variable that holds values of the Go type T.  It panics
+// if T has no expression Type.
+func NewVar[T any]() *TypedVar[T] {
+	t, ok := typeOf[T]()
+	if !ok {
+		var zero T
+		panic(errors.Errorf("%T has no expression Type", zero))
+	}
+	ve, ok := t.Var().(VarExpr)
+	if !ok {
+		panic(errors.Errorf("%v is not an expression", t.Var()))
+	}
+	return &TypedVar[T]{ve}
+}
+
+// Copy copies the variable.
+func (v *TypedVar[T]) Copy(transformations ...Mapper) Expr {
+	copied := v.VarExpr.Copy(transformations...)
+	if ve, ok := copied.(VarExpr); ok {
+		return ApplyMappers(&TypedVar[T]{ve}, transformations...)
+	}
+	return copied
+}
+
+// String represents the variable by its value.
+func (v *TypedVar[T]) String() string {
+	return StringifyExpr(v.Value(), true)
+}
+
+// Get gets the variable's value as a T.
+func (v *TypedVar[T]) Get() (T, error) {
+	return ValueAs[T](v.Value())
+}
+
+// Set sets the variable's value from a T.
+func (v *TypedVar[T]) Set(x T) error {
+	return v.SetValue(Lit(x))
+}
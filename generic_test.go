@@ -0,0 +1,66 @@
+package expr_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/skillian/expr"
+)
+
+func TestEvalAs(t *testing.T) {
+	t.Parallel()
+	sum := expr.Add{expr.Lit(40), expr.Lit(int64(2))}
+	if i, err := expr.EvalAs[int](sum); err != nil || i != 42 {
+		t.Errorf("EvalAs[int](%v) -> %v (err: %v)", sum, i, err)
+	}
+	if i, err := expr.EvalAs[expr.Int64](sum); err != nil || i != 42 {
+		t.Errorf("EvalAs[Int64](%v) -> %v (err: %v)", sum, i, err)
+	}
+	if f, err := expr.EvalAs[float64](sum); err != nil || f != 42 {
+		t.Errorf("EvalAs[float64](%v) -> %v (err: %v)", sum, f, err)
+	}
+	if v, err := expr.EvalAs[interface{}](sum); err != nil || v != int64(42) {
+		t.Errorf("EvalAs[interface{}](%v) -> %v (err: %v)", sum, v, err)
+	}
+	third := expr.Div{expr.Lit(1), expr.Lit(3)}
+	if r, err := expr.EvalAs[*expr.Rational](third); err != nil || (*big.Rat)(r).Cmp(big.NewRat(1, 3)) != 0 {
+		t.Errorf("EvalAs[*Rational](%v) -> %v (err: %v)", third, r, err)
+	}
+	if i, err := expr.EvalAs[int](third); err == nil {
+		t.Errorf("expected EvalAs[int](%v) to fail but got %v", third, i)
+	}
+	if b, err := expr.EvalAs[bool](expr.Gt{sum, expr.Lit(1)}); err != nil || !b {
+		t.Errorf("EvalAs[bool] -> %v (err: %v)", b, err)
+	}
+	if s, err := expr.EvalAs[string](expr.Lit(1)); err == nil {
+		t.Errorf("expected EvalAs[string] to fail but got %q", s)
+	}
+	due := expr.Add{expr.Lit(time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)), expr.Lit(time.Hour)}
+	if tm, err := expr.EvalAs[time.Time](due); err != nil || tm.Hour() != 1 {
+		t.Errorf("EvalAs[time.Time](%v) -> %v (err: %v)", due, tm, err)
+	}
+}
+
+func TestNewVar(t *testing.T) {
+	t.Parallel()
+	v := expr.NewVar[uint16]()
+	if err := v.Set(7); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetValue(expr.Int(8)); err != nil {
+		t.Fatal(err)
+	}
+	if x, err := v.Get(); err != nil || x != 8 {
+		t.Errorf("Get() -> %v (err: %v)", x, err)
+	}
+	if err := v.SetValue(expr.Int(-1)); err == nil {
+		t.Errorf("expected setting %v to -1 to fail", v)
+	}
+	if sum, err := expr.EvalAs[int](expr.Add{v, expr.Lit(2)}); err != nil || sum != 10 {
+		t.Errorf("%v + 2 -> %v (err: %v)", v, sum, err)
+	}
+	if v.String() != "8" {
+		t.Errorf("expected %q but got %q", "8", v.String())
+	}
+}
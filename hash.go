@@ -0,0 +1,152 @@
+package expr
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// HashExpr computes a hash of e's structure.  Expressions for which SameExpr
+// is true have the same hash, so HashExpr can be used to find common
+// subexpressions.
+func HashExpr(e Expr) uint64 {
+	h := fnv.New64a()
+	writeExprKey(h, e)
+	return h.Sum64()
+}
+
+// SameExpr checks if a and b are structurally the same:  They are the same
+// kinds of expressions with the same operands.  Values are the same if they
+// are of the same type and have the same representation and Vars are only the
+// same as themselves.  Dynamic values of pointers, maps, slices, channels and
+// functions are also only the same as themselves.
+func SameExpr(a, b Expr) bool {
+	var sa, sb strings.Builder
+	writeExprKey(&sa, a)
+	writeExprKey(&sb, b)
+	return sa.String() == sb.String()
+}
+
+// writeExprKey writes a representation of e's structure into w.
+func writeExprKey(w io.Writer, e Expr) {
+	if e == nil {
+		io.WriteString(w, "nil")
+		return
+	}
+	v := reflect.ValueOf(e)
+	io.WriteString(w, typeKey(v.Type()))
+	switch e := e.(type) {
+	case Dynamic:
+		writeDynamicKey(w, reflect.Value(e))
+		return
+	case Var:
+		if v.Kind() == reflect.Ptr {
+			fmt.Fprintf(w, "@%x", v.Pointer())
+			return
+		}
+	case ValueSet:
+		fmt.Fprintf(w, "(%d:", len(e))
+		for _, value := range e {
+			writeExprKey(w, value)
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, ")")
+		return
+	case Value:
+		fmt.Fprintf(w, "(%v)", e.Interface())
+		return
+	}
+	io.WriteString(w, "{")
+	writeReflectKey(w, v)
+	io.WriteString(w, "}")
+}
+
+// writeDynamicKey writes a representation of the value wrapped by a Dynamic
+// into w.
+func writeDynamicKey(w io.Writer, v reflect.Value) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		io.WriteString(w, "(invalid)")
+		return
+	}
+	io.WriteString(w, "<"+typeKey(v.Type())+">")
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		fmt.Fprintf(w, "@%x", v.Pointer())
+		return
+	case reflect.Slice:
+		fmt.Fprintf(w, "@%x:%d", v.Pointer(), v.Len())
+		return
+	}
+	if v.CanInterface() {
+		fmt.Fprintf(w, "(%#v)", v.Interface())
+		return
+	}
+	fmt.Fprintf(w, "(%v)", v)
+}
+
+// writeReflectKey writes a representation of the fields or elements of an
+// expression into w.
+func writeReflectKey(w io.Writer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			io.WriteString(w, "nil")
+			return
+		}
+		writeReflectKey(w, v.Elem())
+		return
+	case reflect.Interface:
+		if v.IsNil() {
+			io.WriteString(w, "nil")
+			return
+		}
+		if v.CanInterface() {
+			if e, ok := v.Interface().(Expr); ok {
+				writeExprKey(w, e)
+				return
+			}
+		}
+		io.WriteString(w, typeKey(v.Elem().Type()))
+		writeReflectKey(w, v.Elem())
+		return
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeFieldKey(w, v.Field(i))
+			io.WriteString(w, ";")
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(w, "%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			writeFieldKey(w, v.Index(i))
+			io.WriteString(w, ",")
+		}
+		return
+	}
+	fmt.Fprintf(w, "%v", v)
+}
+
+// writeFieldKey writes the representation of a field or element of an
+// expression into w.
+func writeFieldKey(w io.Writer, v reflect.Value) {
+	if v.Kind() != reflect.Interface && v.CanInterface() {
+		if e, ok := v.Interface().(Expr); ok {
+			writeExprKey(w, e)
+			return
+		}
+	}
+	writeReflectKey(w, v)
+}
+
+// typeKey gets a unique name of the type t.
+func typeKey(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
@@ -0,0 +1,54 @@
+package expr_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestSameExpr(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	attr := func(name expr.String) expr.Attr {
+		return expr.Attr{ValueExpr: expr.ValueOf(struct{ A, B int }{1, 2}), Name: name}
+	}
+	type point struct{ X, Y int }
+	p, q := &point{1, 2}, &point{1, 2}
+	m := map[string]int{"a": 1}
+	tcs := []struct {
+		a, b expr.Expr
+		same bool
+	}{
+		{expr.Add{expr.Int(1), &x}, expr.Add{expr.Int(1), &x}, true},
+		{expr.Add{expr.Int(1), &x}, expr.Add{expr.Int(1), &y}, false},
+		{expr.Add{expr.Int(1), &x}, expr.Sub{expr.Int(1), &x}, false},
+		{expr.Add{expr.Int(1), &x}, expr.Add{expr.Int64(1), &x}, false},
+		{expr.Eq{expr.Int(1), expr.Int(2)}, expr.Ne{expr.Int(1), expr.Int(2)}, false},
+		{expr.All{expr.True, expr.Gt{&x, expr.Int(0)}}, expr.All{expr.True, expr.Gt{&x, expr.Int(0)}}, true},
+		{expr.All{expr.True}, expr.Any{expr.True}, false},
+		{(*expr.Rational)(big.NewRat(1, 3)), (*expr.Rational)(big.NewRat(2, 6)), true},
+		{attr("A"), attr("A"), true},
+		{attr("A"), attr("B"), false},
+		{expr.Cast{ValueExpr: &x, Type: expr.Int64Type}, expr.Cast{ValueExpr: &x, Type: expr.Int64Type}, true},
+		{expr.Cast{ValueExpr: &x, Type: expr.Int64Type}, expr.Cast{ValueExpr: &x, Type: expr.Float64Type}, false},
+		{expr.Set{expr.Int(1), expr.String("a")}, expr.Set{expr.Int(1), expr.String("a")}, true},
+		{expr.Set{expr.Int(1)}, expr.Set{expr.Int(1), expr.Int(1)}, false},
+		{expr.ValueSet{expr.Int(1)}, expr.ValueSet{expr.Int64(1)}, false},
+		{expr.ValueSet{expr.Int(1), nil}, expr.ValueSet{expr.Int(1), nil}, true},
+		{expr.ValueOf(p), expr.ValueOf(p), true},
+		{expr.ValueOf(p), expr.ValueOf(q), false},
+		{expr.ValueOf(m), expr.ValueOf(map[string]int{"a": 1}), false},
+		{expr.ValueOf(point{1, 2}), expr.ValueOf(point{1, 2}), true},
+		{expr.ValueOf(point{1, 2}), expr.ValueOf(struct{ X, Y int }{1, 2}), false},
+		{expr.ValueOf(uint8(1)), expr.ValueOf(int8(1)), false},
+	}
+	for _, tc := range tcs {
+		if same := expr.SameExpr(tc.a, tc.b); same != tc.same {
+			t.Errorf("SameExpr(%v, %v) -> %v (expected %v)", tc.a, tc.b, same, tc.same)
+		}
+		if tc.same && expr.HashExpr(tc.a) != expr.HashExpr(tc.b) {
+			t.Errorf("HashExpr(%v) != HashExpr(%v)", tc.a, tc.b)
+		}
+	}
+}
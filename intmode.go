@@ -0,0 +1,212 @@
+package expr
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+)
+
+// IntMode selects how arithmetic between fixed-width integers (such as Int,
+// Int64 and Uint64) is performed.
+type IntMode int
+
+const (
+	// RationalInts performs integer arithmetic with arbitrary precision
+	// rational numbers so the result type depends on its magnitude.  This
+	// is the default.
+	RationalInts IntMode = iota
+
+	// WrapInts keeps results in their native width and wraps around on
+	// overflow like Go does.
+	WrapInts
+
+	// SaturateInts keeps results in their native width and clamps them to
+	// the minimum or maximum value of their type on overflow.
+	SaturateInts
+
+	// CheckedInts keeps results in their native width and results in an
+	// *OverflowError on overflow.
+	CheckedInts
+)
+
+type intModeKey struct{}
+
+// WithIntMode returns a copy of ctx whose evaluations perform fixed-width
+// integer arithmetic according to mode.
+func WithIntMode(ctx context.Context, mode IntMode) context.Context {
+	return context.WithValue(ctx, intModeKey{}, mode)
+}
+
+// IntModeFromContext gets the IntMode of the context.  The default is
+// RationalInts.
+func IntModeFromContext(ctx context.Context) IntMode {
+	mode, _ := ctx.Value(intModeKey{}).(IntMode)
+	return mode
+}
+
+// arithOp is an arithmetic operation that can be performed on rational,
+// fixed-width integer, floating point and complex operands.
+type arithOp struct {
+	name    string
+	rat     func(z, x, y *big.Rat) *big.Rat
+	int     func(z, x, y *big.Int) *big.Int
+	float   func(x, y float64) float64
+	complex func(x, y complex128) complex128
+
+	// divides is true if the operation can divide by zero.
+	divides bool
+}
+
+var (
+	opAdd = arithOp{"add", (*big.Rat).Add, (*big.Int).Add, func(x, y float64) float64 { return x + y }, func(x, y complex128) complex128 { return x + y }, false}
+	opSub = arithOp{"subtract", (*big.Rat).Sub, (*big.Int).Sub, func(x, y float64) float64 { return x - y }, func(x, y complex128) complex128 { return x - y }, false}
+	opMul = arithOp{"multiply", (*big.Rat).Mul, (*big.Int).Mul, func(x, y float64) float64 { return x * y }, func(x, y complex128) complex128 { return x * y }, false}
+	opQuo = arithOp{"divide", (*big.Rat).Quo, (*big.Int).Quo, func(x, y float64) float64 { return x / y }, func(x, y complex128) complex128 { return x / y }, true}
+)
+
+// intKind describes the width and signedness of a fixed-width integer type.
+type intKind struct {
+	bits   uint
+	signed bool
+}
+
+// intKindOf gets the intKind of v if v is a fixed-width integer.
+func intKindOf(v Value) (intKind, bool) {
+	switch v.(type) {
+	case Int:
+		return intKind{strconv.IntSize, true}, true
+	case Int8:
+		return intKind{8, true}, true
+	case Int16:
+		return intKind{16, true}, true
+	case Int32:
+		return intKind{32, true}, true
+	case Int64:
+		return intKind{64, true}, true
+	case Uint:
+		return intKind{strconv.IntSize, false}, true
+	case Uint8:
+		return intKind{8, false}, true
+	case Uint16:
+		return intKind{16, false}, true
+	case Uint32:
+		return intKind{32, false}, true
+	case Uint64:
+		return intKind{64, false}, true
+	}
+	return intKind{}, false
+}
+
+// isFixedInt returns true if v is a fixed-width integer.
+func isFixedInt(v Value) bool {
+	_, ok := intKindOf(v)
+	return ok
+}
+
+// bounds gets the minimum and maximum values of the kind.
+func (k intKind) bounds() (min, max *big.Int) {
+	max = new(big.Int).Lsh(big.NewInt(1), k.bits)
+	if !k.signed {
+		return new(big.Int), max.Sub(max, big.NewInt(1))
+	}
+	max.Rsh(max, 1)
+	min = new(big.Int).Neg(max)
+	return min, max.Sub(max, big.NewInt(1))
+}
+
+// wrap truncates b to the width of the kind using two's complement.
+func (k intKind) wrap(b *big.Int) *big.Int {
+	mod := new(big.Int).Lsh(big.NewInt(1), k.bits)
+	r := new(big.Int).And(b, new(big.Int).Sub(mod, big.NewInt(1)))
+	if k.signed && r.Bit(int(k.bits)-1) == 1 {
+		r.Sub(r, mod)
+	}
+	return r
+}
+
+// makeInt creates a fixed-width integer Value of the same type as like from
+// b.  b must fit into like's type.
+func makeInt(like Value, b *big.Int) Value {
+	switch like.(type) {
+	case Int:
+		return Int(b.Int64())
+	case Int8:
+		return Int8(b.Int64())
+	case Int16:
+		return Int16(b.Int64())
+	case Int32:
+		return Int32(b.Int64())
+	case Int64:
+		return Int64(b.Int64())
+	case Uint:
+		return Uint(b.Uint64())
+	case Uint8:
+		return Uint8(b.Uint64())
+	case Uint16:
+		return Uint16(b.Uint64())
+	case Uint32:
+		return Uint32(b.Uint64())
+	case Uint64:
+		return Uint64(b.Uint64())
+	}
+	return (*Rational)(new(big.Rat).SetInt(b))
+}
+
+// intArithmeticHelper performs op on left and right.  If both operands are
+// fixed-width integers and the context's IntMode is not RationalInts, the
+// result has the type of the wider operand (or the left operand's type if
+// they're the same width) and overflow is handled according to the IntMode.
+// If right is a complex number, the arithmetic is performed with complex
+// numbers, if it's a floating point number, it's performed with float64s and
+// otherwise it's performed with rational numbers.
+func intArithmeticHelper(ctx context.Context, op arithOp, left Number, v Value) (Value, error) {
+	if isComplex(v) {
+		return complexArithmeticHelper(ctx, op, left, v)
+	}
+	right, ok := v.(Number)
+	if !ok {
+		return nil, typeMismatch(op.name, left, v)
+	}
+	mode := IntModeFromContext(ctx)
+	if mode == RationalInts || !isFixedInt(left) || !isFixedInt(right) {
+		if op.divides {
+			return numberDivideHelper(ctx, left, right)
+		}
+		return numberArithmeticHelper(op, left, right)
+	}
+	var rats [2]big.Rat
+	left.Rat(&rats[0])
+	right.Rat(&rats[1])
+	x, y := rats[0].Num(), rats[1].Num()
+	if op.divides && y.Sign() == 0 {
+		return nil, divisionByZero(left, right)
+	}
+	return fixedIntResult(mode, left, right, op.int(new(big.Int), x, y))
+}
+
+// fixedIntResult converts the result of an operation between the fixed-width
+// integers left and right into the type of the wider operand (or the left
+// operand's type if they're the same width) and handles overflow according
+// to mode.
+func fixedIntResult(mode IntMode, left, right Number, result *big.Int) (Value, error) {
+	lk, _ := intKindOf(left)
+	rk, _ := intKindOf(right)
+	like, kind := Value(left), lk
+	if rk.bits > lk.bits {
+		like, kind = right, rk
+	}
+	min, max := kind.bounds()
+	if result.Cmp(min) >= 0 && result.Cmp(max) <= 0 {
+		return makeInt(like, result), nil
+	}
+	switch mode {
+	case WrapInts:
+		return makeInt(like, kind.wrap(result)), nil
+	case SaturateInts:
+		if result.Sign() < 0 {
+			return makeInt(like, min), nil
+		}
+		return makeInt(like, max), nil
+	}
+	return nil, overflow(like.(TypedValueExpr).Type(), left, right)
+}
@@ -1,9 +1,8 @@
 package expr
 
 import (
+	"context"
 	"math/big"
-
-	"github.com/skillian/errors"
 )
 
 // Int wraps a Go int value.
@@ -31,9 +30,9 @@ func (i Int) Copy(transformations ...Mapper) Expr {
 // is returned.
 func (i Int) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(i, n), nil
+		return cmpNumbers(i, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", i, v)
+	return 0, notComparable(i, v, nil)
 }
 
 // Eval the expression.
@@ -44,34 +43,42 @@ func (i Int) Eval() (interface{}, error) {
 // EvalAdd adds the given number to the current Int.  If v is not a number,
 // an error is returned.
 func (i Int) EvalAdd(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Add), nil
-	}
-	return nil, errors.Errorf("cannot add %v to %v", i, v)
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Int) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
 }
 
 // EvalSubtract subtracts v from i.
 func (i Int) EvalSubtract(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Sub), nil
-	}
-	return nil, errors.Errorf("cannot subtract %v from %v", i, v)
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Int) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
 }
 
 // EvalMultiply multiplies i by v.
 func (i Int) EvalMultiply(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Mul), nil
-	}
-	return nil, errors.Errorf("cannot multiply %v by %v", i, v)
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Int) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
 }
 
 // EvalDivide divides i by v.
 func (i Int) EvalDivide(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Quo), nil
-	}
-	return nil, errors.Errorf("cannot divide %v by %v", i, v)
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Int) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
 }
 
 // Interface of the expression.
@@ -101,11 +108,12 @@ func (i *Int) Value() Value {
 
 // SetValue of the expression.
 func (i *Int) SetValue(v Value) error {
-	if v, ok := v.(Int); ok {
-		*i = v
-		return nil
+	c, err := Convert(v, IntType)
+	if err != nil {
+		return err
 	}
-	return TypeErrorFromExpectedAndActual(i, v)
+	*i = c.(Int)
+	return nil
 }
 
 // Int64 wraps a Go int64 value.
@@ -132,9 +140,9 @@ func (i Int64) Copy(transformations ...Mapper) Expr {
 // Cmp compares i to v.
 func (i Int64) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(i, n), nil
+		return cmpNumbers(i, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", i, v)
+	return 0, notComparable(i, v, nil)
 }
 
 // Eval the expression.
@@ -144,7 +152,7 @@ func (i Int64) Eval() (interface{}, error) {
 
 // Interface of the expression.
 func (i Int64) Interface() interface{} {
-	return int(i)
+	return int64(i)
 }
 
 // EvalValue evaluates the expression to a a value.
@@ -154,34 +162,42 @@ func (i Int64) EvalValue() (Value, error) {
 
 // EvalAdd adds v to i.
 func (i Int64) EvalAdd(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Add), nil
-	}
-	return nil, errors.Errorf("cannot add %v to %v", i, v)
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Int64) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
 }
 
 // EvalSubtract subtracts v from i.
 func (i Int64) EvalSubtract(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Sub), nil
-	}
-	return nil, errors.Errorf("cannot subtract %v from %v", i, v)
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Int64) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
 }
 
 // EvalMultiply multiplies i by v.
 func (i Int64) EvalMultiply(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Mul), nil
-	}
-	return nil, errors.Errorf("cannot multiply %v by %v", i, v)
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Int64) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
 }
 
 // EvalDivide divides i by v.
 func (i Int64) EvalDivide(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Quo), nil
-	}
-	return nil, errors.Errorf("cannot divide %v by %v", i, v)
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Int64) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
 }
 
 // Rat stores the value of i into r.
@@ -201,14 +217,11 @@ func (i *Int64) Value() Value {
 
 // SetValue of the expression.
 func (i *Int64) SetValue(v Value) error {
-	switch v := v.(type) {
-	case Int64:
-		*i = v
-	case Int:
-		*i = Int64(int64(int(v)))
-	default:
-		return TypeErrorFromExpectedAndActual(i, v)
+	c, err := Convert(v, Int64Type)
+	if err != nil {
+		return err
 	}
+	*i = c.(Int64)
 	return nil
 }
 
@@ -236,9 +249,9 @@ func (i Uint64) Copy(transformations ...Mapper) Expr {
 // Cmp compares i to v.
 func (i Uint64) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(i, n), nil
+		return cmpNumbers(i, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", i, v)
+	return 0, notComparable(i, v, nil)
 }
 
 // Eval the expression.
@@ -248,7 +261,7 @@ func (i Uint64) Eval() (interface{}, error) {
 
 // Interface of the expression.
 func (i Uint64) Interface() interface{} {
-	return int(i)
+	return uint64(i)
 }
 
 // EvalValue evaluates the expression to a a value.
@@ -258,34 +271,42 @@ func (i Uint64) EvalValue() (Value, error) {
 
 // EvalAdd adds v to i.
 func (i Uint64) EvalAdd(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Add), nil
-	}
-	return nil, errors.Errorf("cannot add %v to %v", i, v)
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Uint64) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
 }
 
 // EvalSubtract subtracts v from i.
 func (i Uint64) EvalSubtract(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Sub), nil
-	}
-	return nil, errors.Errorf("cannot subtract %v from %v", i, v)
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Uint64) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
 }
 
 // EvalMultiply multiplies i by v.
 func (i Uint64) EvalMultiply(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Mul), nil
-	}
-	return nil, errors.Errorf("cannot multiply %v by %v", i, v)
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Uint64) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
 }
 
 // EvalDivide divides i by v.
 func (i Uint64) EvalDivide(v Value) (Value, error) {
-	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(i, n, (*big.Rat).Quo), nil
-	}
-	return nil, errors.Errorf("cannot divide %v by %v", i, v)
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Uint64) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
 }
 
 // Rat stores i's value into r.
@@ -307,18 +328,773 @@ func (i *Uint64) Value() Value {
 
 // SetValue of the expression.
 func (i *Uint64) SetValue(v Value) error {
-	switch v := v.(type) {
-	case Uint64:
-		*i = v
-	case Int:
-		if v < 0 {
-			return errors.Errorf("cannot set Uint64 from negative integer: %v", v)
-		}
-		*i = Uint64(uint64(int(v)))
-	case Int64:
-
-	default:
-		return TypeErrorFromExpectedAndActual(i, v)
+	c, err := Convert(v, Uint64Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Uint64)
+	return nil
+}
+
+// Int8 wraps a Go int8 value.
+type Int8 int8
+
+type int8type struct{}
+
+// Int8Type is the expr.Type of Int8s.
+var Int8Type Type = int8type{}
+
+func (t int8type) Zero() Value {
+	return Int8(0)
+}
+
+func (t int8type) Var() Var {
+	return new(Int8)
+}
+
+// Copy the expression.
+func (i Int8) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Int8) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Int8) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Int8) Interface() interface{} {
+	return int8(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Int8) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Int8) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Int8) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Int8) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Int8) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Int8) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Int8) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Int8) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Int8) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Int8) Rat(r *big.Rat) {
+	r.SetInt64(int64(i))
+}
+
+// Type gets the expression type of i: Int8Type.
+func (i Int8) Type() Type {
+	return Int8Type
+}
+
+// Value of the expression.
+func (i *Int8) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Int8) SetValue(v Value) error {
+	c, err := Convert(v, Int8Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Int8)
+	return nil
+}
+
+// Int16 wraps a Go int16 value.
+type Int16 int16
+
+type int16type struct{}
+
+// Int16Type is the expr.Type of Int16s.
+var Int16Type Type = int16type{}
+
+func (t int16type) Zero() Value {
+	return Int16(0)
+}
+
+func (t int16type) Var() Var {
+	return new(Int16)
+}
+
+// Copy the expression.
+func (i Int16) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Int16) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Int16) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Int16) Interface() interface{} {
+	return int16(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Int16) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Int16) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Int16) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Int16) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Int16) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Int16) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Int16) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Int16) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Int16) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Int16) Rat(r *big.Rat) {
+	r.SetInt64(int64(i))
+}
+
+// Type gets the expression type of i: Int16Type.
+func (i Int16) Type() Type {
+	return Int16Type
+}
+
+// Value of the expression.
+func (i *Int16) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Int16) SetValue(v Value) error {
+	c, err := Convert(v, Int16Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Int16)
+	return nil
+}
+
+// Int32 wraps a Go int32 value.
+type Int32 int32
+
+type int32type struct{}
+
+// Int32Type is the expr.Type of Int32s.
+var Int32Type Type = int32type{}
+
+func (t int32type) Zero() Value {
+	return Int32(0)
+}
+
+func (t int32type) Var() Var {
+	return new(Int32)
+}
+
+// Copy the expression.
+func (i Int32) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Int32) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Int32) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Int32) Interface() interface{} {
+	return int32(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Int32) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Int32) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Int32) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Int32) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Int32) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Int32) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Int32) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Int32) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Int32) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Int32) Rat(r *big.Rat) {
+	r.SetInt64(int64(i))
+}
+
+// Type gets the expression type of i: Int32Type.
+func (i Int32) Type() Type {
+	return Int32Type
+}
+
+// Value of the expression.
+func (i *Int32) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Int32) SetValue(v Value) error {
+	c, err := Convert(v, Int32Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Int32)
+	return nil
+}
+
+// Uint wraps a Go uint value.
+type Uint uint
+
+type uinttype struct{}
+
+// UintType is the expr.Type of Uints.
+var UintType Type = uinttype{}
+
+func (t uinttype) Zero() Value {
+	return Uint(0)
+}
+
+func (t uinttype) Var() Var {
+	return new(Uint)
+}
+
+// Copy the expression.
+func (i Uint) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Uint) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Uint) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Uint) Interface() interface{} {
+	return uint(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Uint) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Uint) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Uint) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Uint) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Uint) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Uint) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Uint) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Uint) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Uint) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Uint) Rat(r *big.Rat) {
+	r.SetInt(new(big.Int).SetUint64(uint64(i)))
+}
+
+// Type gets the expression type of i: UintType.
+func (i Uint) Type() Type {
+	return UintType
+}
+
+// Value of the expression.
+func (i *Uint) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Uint) SetValue(v Value) error {
+	c, err := Convert(v, UintType)
+	if err != nil {
+		return err
+	}
+	*i = c.(Uint)
+	return nil
+}
+
+// Uint8 wraps a Go uint8 value.
+type Uint8 uint8
+
+type uint8type struct{}
+
+// Uint8Type is the expr.Type of Uint8s.
+var Uint8Type Type = uint8type{}
+
+func (t uint8type) Zero() Value {
+	return Uint8(0)
+}
+
+func (t uint8type) Var() Var {
+	return new(Uint8)
+}
+
+// Copy the expression.
+func (i Uint8) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Uint8) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Uint8) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Uint8) Interface() interface{} {
+	return uint8(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Uint8) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Uint8) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Uint8) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Uint8) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Uint8) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Uint8) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Uint8) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Uint8) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Uint8) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Uint8) Rat(r *big.Rat) {
+	r.SetInt(new(big.Int).SetUint64(uint64(i)))
+}
+
+// Type gets the expression type of i: Uint8Type.
+func (i Uint8) Type() Type {
+	return Uint8Type
+}
+
+// Value of the expression.
+func (i *Uint8) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Uint8) SetValue(v Value) error {
+	c, err := Convert(v, Uint8Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Uint8)
+	return nil
+}
+
+// Uint16 wraps a Go uint16 value.
+type Uint16 uint16
+
+type uint16type struct{}
+
+// Uint16Type is the expr.Type of Uint16s.
+var Uint16Type Type = uint16type{}
+
+func (t uint16type) Zero() Value {
+	return Uint16(0)
+}
+
+func (t uint16type) Var() Var {
+	return new(Uint16)
+}
+
+// Copy the expression.
+func (i Uint16) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Uint16) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Uint16) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Uint16) Interface() interface{} {
+	return uint16(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Uint16) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Uint16) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Uint16) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Uint16) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Uint16) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Uint16) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Uint16) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Uint16) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Uint16) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Uint16) Rat(r *big.Rat) {
+	r.SetInt(new(big.Int).SetUint64(uint64(i)))
+}
+
+// Type gets the expression type of i: Uint16Type.
+func (i Uint16) Type() Type {
+	return Uint16Type
+}
+
+// Value of the expression.
+func (i *Uint16) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Uint16) SetValue(v Value) error {
+	c, err := Convert(v, Uint16Type)
+	if err != nil {
+		return err
+	}
+	*i = c.(Uint16)
+	return nil
+}
+
+// Uint32 wraps a Go uint32 value.
+type Uint32 uint32
+
+type uint32type struct{}
+
+// Uint32Type is the expr.Type of Uint32s.
+var Uint32Type Type = uint32type{}
+
+func (t uint32type) Zero() Value {
+	return Uint32(0)
+}
+
+func (t uint32type) Var() Var {
+	return new(Uint32)
+}
+
+// Copy the expression.
+func (i Uint32) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(i, transformations...)
+}
+
+// Cmp compares i to v.
+func (i Uint32) Cmp(v Value) (int, error) {
+	if n, ok := v.(Number); ok {
+		return cmpNumbers(i, n)
+	}
+	return 0, notComparable(i, v, nil)
+}
+
+// Eval the expression.
+func (i Uint32) Eval() (interface{}, error) {
+	return i.Interface(), nil
+}
+
+// Interface of the expression.
+func (i Uint32) Interface() interface{} {
+	return uint32(i)
+}
+
+// EvalValue evaluates the expression to a a value.
+func (i Uint32) EvalValue() (Value, error) {
+	return i, nil
+}
+
+// EvalAdd adds v to i.
+func (i Uint32) EvalAdd(v Value) (Value, error) {
+	return i.EvalAddContext(context.Background(), v)
+}
+
+// EvalAddContext adds v to i under ctx.
+func (i Uint32) EvalAddContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opAdd, i, v)
+}
+
+// EvalSubtract subtracts v from i.
+func (i Uint32) EvalSubtract(v Value) (Value, error) {
+	return i.EvalSubtractContext(context.Background(), v)
+}
+
+// EvalSubtractContext subtracts v from i under ctx.
+func (i Uint32) EvalSubtractContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opSub, i, v)
+}
+
+// EvalMultiply multiplies i by v.
+func (i Uint32) EvalMultiply(v Value) (Value, error) {
+	return i.EvalMultiplyContext(context.Background(), v)
+}
+
+// EvalMultiplyContext multiplies i by v under ctx.
+func (i Uint32) EvalMultiplyContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opMul, i, v)
+}
+
+// EvalDivide divides i by v.
+func (i Uint32) EvalDivide(v Value) (Value, error) {
+	return i.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides i by v under ctx.
+func (i Uint32) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
+	return intArithmeticHelper(ctx, opQuo, i, v)
+}
+
+// Rat stores the value of i into r.
+func (i Uint32) Rat(r *big.Rat) {
+	r.SetInt(new(big.Int).SetUint64(uint64(i)))
+}
+
+// Type gets the expression type of i: Uint32Type.
+func (i Uint32) Type() Type {
+	return Uint32Type
+}
+
+// Value of the expression.
+func (i *Uint32) Value() Value {
+	return *i
+}
+
+// SetValue of the expression.
+func (i *Uint32) SetValue(v Value) error {
+	c, err := Convert(v, Uint32Type)
+	if err != nil {
+		return err
 	}
+	*i = c.(Uint32)
 	return nil
 }
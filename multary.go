@@ -1,5 +1,7 @@
 package expr
 
+import "context"
+
 type multaryBool []BoolExpr
 
 func (m multaryBool) copy(transformations ...Mapper) multaryBool {
@@ -23,13 +25,18 @@ type All multaryBool
 
 // Copy the expression
 func (a All) Copy(transformations ...Mapper) Expr {
-	return ApplyMappers(All(multaryBool(a).copy()), transformations...)
+	return ApplyMappers(All(multaryBool(a).copy(transformations...)), transformations...)
 }
 
 // EvalBool evaluates the expression to a bool result.
 func (a All) EvalBool() (bool, error) {
+	return EvalBoolContext(context.Background(), a)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (a All) EvalBoolContext(ctx context.Context) (bool, error) {
 	for _, e := range a {
-		ok, err := e.EvalBool()
+		ok, err := evalBoolContext(ctx, e)
 		if err != nil {
 			return false, err
 		}
@@ -61,13 +68,18 @@ type Any []BoolExpr
 
 // Copy the expression.
 func (a Any) Copy(transformations ...Mapper) Expr {
-	return ApplyMappers(Any(multaryBool(a).copy()), transformations...)
+	return ApplyMappers(Any(multaryBool(a).copy(transformations...)), transformations...)
 }
 
 // EvalBool evaluates the expression to a bool result.
 func (a Any) EvalBool() (bool, error) {
+	return EvalBoolContext(context.Background(), a)
+}
+
+// EvalBoolContext evaluates the expression to a bool result under ctx.
+func (a Any) EvalBoolContext(ctx context.Context) (bool, error) {
 	for _, e := range a {
-		ok, err := e.EvalBool()
+		ok, err := evalBoolContext(ctx, e)
 		if err != nil {
 			return false, err
 		}
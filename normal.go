@@ -0,0 +1,277 @@
+package expr
+
+// NNF converts e into negation normal form:  Nots are pushed inward with De
+// Morgan's laws until they only apply to expressions other than All, Any,
+// Not and comparisons.  Negated comparisons are replaced by their
+// complements (e.g. Not{Gt{a, b}} becomes Le{a, b}), which assumes that
+// their operands are totally ordered.  NNF can be used as a Mapper.
+func NNF(e Expr) Expr {
+	return mapBoolOperands(e, NNF, func(e Expr) Expr {
+		if n, ok := e.(Not); ok {
+			return negate(n[0])
+		}
+		return e
+	})
+}
+
+// negate gets the negation normal form of the negation of e.  e must
+// already be in negation normal form.
+func negate(e BoolExpr) BoolExpr {
+	switch e := e.(type) {
+	case Bool:
+		return !e
+	case Not:
+		return e[0]
+	case All:
+		negated := make(Any, len(e))
+		for i, operand := range e {
+			negated[i] = negate(operand)
+		}
+		return negated
+	case Any:
+		negated := make(All, len(e))
+		for i, operand := range e {
+			negated[i] = negate(operand)
+		}
+		return negated
+	case Eq:
+		return Ne(e)
+	case Ne:
+		return Eq(e)
+	case Gt:
+		return Le(e)
+	case Ge:
+		return Lt(e)
+	case Lt:
+		return Ge(e)
+	case Le:
+		return Gt(e)
+	}
+	return Not{e}
+}
+
+// Flatten merges the operands of All and Any expressions nested directly
+// within other All and Any expressions of the same kind into their parents,
+// e.g. All{a, All{b, c}} becomes All{a, b, c}.  Flatten can be used as a
+// Mapper.
+func Flatten(e Expr) Expr {
+	return mapBoolOperands(e, Flatten, func(e Expr) Expr {
+		switch e := e.(type) {
+		case All:
+			return All(flatten(e, func(e BoolExpr) ([]BoolExpr, bool) {
+				a, ok := e.(All)
+				return a, ok
+			}))
+		case Any:
+			return Any(flatten(e, func(e BoolExpr) ([]BoolExpr, bool) {
+				a, ok := e.(Any)
+				return a, ok
+			}))
+		}
+		return e
+	})
+}
+
+func flatten(operands []BoolExpr, same func(e BoolExpr) ([]BoolExpr, bool)) []BoolExpr {
+	flat := make([]BoolExpr, 0, len(operands))
+	for _, operand := range operands {
+		if inner, ok := same(operand); ok {
+			flat = append(flat, inner...)
+			continue
+		}
+		flat = append(flat, operand)
+	}
+	return flat
+}
+
+// mapBoolOperands applies f to e's operands if e is an All, Any or Not and
+// then applies g to the result.
+func mapBoolOperands(e Expr, f, g Mapper) Expr {
+	switch e.(type) {
+	case All, Any, Not:
+	default:
+		return e
+	}
+	operands := Operands(e)
+	for i, operand := range operands {
+		operands[i] = f(operand)
+	}
+	mapped, err := WithOperands(e, operands)
+	if err != nil {
+		return e
+	}
+	return g(mapped)
+}
+
+// CNF converts e into conjunctive normal form:  an All of Anys whose
+// operands are literals (expressions other than All and Any, optionally
+// negated by Not).  Clauses with one literal are represented by the literal
+// itself and a CNF with one clause is represented by the clause.
+//
+// Converting to CNF can grow the expression exponentially, so a *LimitError
+// is returned if the result would have more than limit clauses.  If limit
+// is 0, the number of clauses is unlimited.
+func CNF(e BoolExpr, limit int) (BoolExpr, error) {
+	clauses, err := normalForm(e, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	return clauses.expr(false), nil
+}
+
+// DNF converts e into disjunctive normal form:  an Any of Alls whose
+// operands are literals.  It is the dual of CNF and is limited to limit
+// terms the same way.
+func DNF(e BoolExpr, limit int) (BoolExpr, error) {
+	terms, err := normalForm(e, limit, true)
+	if err != nil {
+		return nil, err
+	}
+	return terms.expr(true), nil
+}
+
+// CNFMapper creates a Mapper that converts expressions into conjunctive
+// normal form.  Expressions that would have more than limit clauses are
+// converted into negation normal form instead.
+func CNFMapper(limit int) Mapper {
+	return normalFormMapper(CNF, limit)
+}
+
+// DNFMapper creates a Mapper that converts expressions into disjunctive
+// normal form.  Expressions that would have more than limit terms are
+// converted into negation normal form instead.
+func DNFMapper(limit int) Mapper {
+	return normalFormMapper(DNF, limit)
+}
+
+func normalFormMapper(f func(e BoolExpr, limit int) (BoolExpr, error), limit int) Mapper {
+	return func(e Expr) Expr {
+		be, ok := e.(BoolExpr)
+		if !ok {
+			return e
+		}
+		nf, err := f(be, limit)
+		if err != nil {
+			return NNF(e)
+		}
+		return nf
+	}
+}
+
+// clauses are the clauses of a CNF or the terms of a DNF.
+type clauses [][]BoolExpr
+
+// normalForm gets the clauses of e's CNF or, if dual is true, the terms of
+// its DNF.
+func normalForm(e BoolExpr, limit int, dual bool) (clauses, error) {
+	nnf, ok := NNF(e).(BoolExpr)
+	if !ok {
+		return clauses{{e}}, nil
+	}
+	return normalFormOf(nnf, limit, dual)
+}
+
+// normalFormOf gets the clauses of an expression already in negation normal
+// form.  For a CNF, the clauses of an All's operands are joined and the
+// clauses of an Any's operands are distributed over each other.  A DNF is
+// the reverse.
+func normalFormOf(e BoolExpr, limit int, dual bool) (clauses, error) {
+	var operands []BoolExpr
+	var join bool
+	switch e := e.(type) {
+	case Bool:
+		// true is the empty CNF and false has an empty clause:
+		if bool(e) != dual {
+			return clauses{}, nil
+		}
+		return clauses{{}}, nil
+	case All:
+		operands, join = e, !dual
+	case Any:
+		operands, join = e, dual
+	default:
+		return clauses{{e}}, nil
+	}
+	result := clauses{}
+	if !join {
+		result = clauses{{}}
+	}
+	for _, operand := range operands {
+		cs, err := normalFormOf(operand, limit, dual)
+		if err != nil {
+			return nil, err
+		}
+		if join {
+			result = append(result, cs...)
+		} else if limit > 0 && len(result)*len(cs) > limit {
+			return nil, &LimitError{Kind: ClauseLimit, Max: limit, Expr: e}
+		} else {
+			result = result.distribute(cs)
+		}
+		if limit > 0 && len(result) > limit {
+			return nil, &LimitError{Kind: ClauseLimit, Max: limit, Expr: e}
+		}
+	}
+	return result, nil
+}
+
+// distribute combines every clause of cs with every clause of o.
+func (cs clauses) distribute(o clauses) clauses {
+	result := make(clauses, 0, len(cs)*len(o))
+	for _, c := range cs {
+		for _, d := range o {
+			clause := make([]BoolExpr, 0, len(c)+len(d))
+			clause = append(clause, c...)
+			result = append(result, appendLiterals(clause, d))
+		}
+	}
+	return result
+}
+
+// appendLiterals appends the literals to the clause unless they're already
+// in it.
+func appendLiterals(clause []BoolExpr, literals []BoolExpr) []BoolExpr {
+outer:
+	for _, lit := range literals {
+		for _, existing := range clause {
+			if SameExpr(existing, lit) {
+				continue outer
+			}
+		}
+		clause = append(clause, lit)
+	}
+	return clause
+}
+
+// expr builds the normal form of the clauses.  For a CNF, the clauses are
+// joined by All and their literals by Any.  For a DNF, the reverse.
+func (cs clauses) expr(dual bool) BoolExpr {
+	outer := make([]BoolExpr, 0, len(cs))
+	for _, c := range cs {
+		c = appendLiterals(nil, c)
+		switch len(c) {
+		case 0:
+			// An empty clause is false in a CNF and an empty term
+			// is true in a DNF; either decides the whole result.
+			return Bool(dual)
+		case 1:
+			outer = append(outer, c[0])
+		default:
+			if dual {
+				outer = append(outer, All(c))
+			} else {
+				outer = append(outer, Any(c))
+			}
+		}
+	}
+	switch len(outer) {
+	case 0:
+		return Bool(!dual)
+	case 1:
+		return outer[0]
+	}
+	if dual {
+		return Any(outer)
+	}
+	return All(outer)
+}
@@ -0,0 +1,169 @@
+package expr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+// boolVars creates n Bool variables and a function that calls f with every
+// combination of their values.
+func boolVars(n int) ([]*expr.Bool, func(f func())) {
+	vars := make([]*expr.Bool, n)
+	for i := range vars {
+		vars[i] = new(expr.Bool)
+	}
+	return vars, func(f func()) {
+		for bits := 0; bits < 1<<n; bits++ {
+			for i, v := range vars {
+				*v = expr.Bool(bits&(1<<i) != 0)
+			}
+			f()
+		}
+	}
+}
+
+// isNormalForm checks if e is an outer of inners of literals.
+func isNormalForm(e expr.Expr, outer, inner func(e expr.Expr) ([]expr.BoolExpr, bool)) bool {
+	isLiteral := func(e expr.Expr) bool {
+		if n, ok := e.(expr.Not); ok {
+			e = n.Operand()
+		}
+		switch e.(type) {
+		case expr.All, expr.Any, expr.Not:
+			return false
+		}
+		return true
+	}
+	isClause := func(e expr.Expr) bool {
+		if lits, ok := inner(e); ok {
+			for _, lit := range lits {
+				if !isLiteral(lit) {
+					return false
+				}
+			}
+			return true
+		}
+		return isLiteral(e)
+	}
+	if clauses, ok := outer(e); ok {
+		for _, c := range clauses {
+			if !isClause(c) {
+				return false
+			}
+		}
+		return true
+	}
+	return isClause(e)
+}
+
+func asAll(e expr.Expr) ([]expr.BoolExpr, bool) {
+	a, ok := e.(expr.All)
+	return a, ok
+}
+
+func asAny(e expr.Expr) ([]expr.BoolExpr, bool) {
+	a, ok := e.(expr.Any)
+	return a, ok
+}
+
+func TestNormalForms(t *testing.T) {
+	t.Parallel()
+	vars, each := boolVars(4)
+	a, b, c, d := vars[0], vars[1], vars[2], vars[3]
+	tcs := []expr.BoolExpr{
+		expr.Not{expr.All{a, expr.Any{b, expr.Not{c}}}},
+		expr.Any{expr.All{a, b}, expr.All{c, d}},
+		expr.All{expr.Any{a, b}, expr.Not{expr.Any{c, expr.Not{d}}}, expr.True},
+		expr.Not{expr.Not{expr.Any{expr.All{a, expr.Not{b}}, expr.False, expr.All{c, expr.Any{d, a}}}}},
+		expr.Any{a, expr.Not{a}},
+		expr.All{a, expr.False},
+	}
+	for _, e := range tcs {
+		cnf, err := expr.CNF(e, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dnf, err := expr.DNF(e, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nnf := expr.NNF(e).(expr.BoolExpr)
+		if !isNormalForm(cnf, asAll, asAny) {
+			t.Errorf("%v: %v is not in CNF", e, cnf)
+		}
+		if !isNormalForm(dnf, asAny, asAll) {
+			t.Errorf("%v: %v is not in DNF", e, dnf)
+		}
+		each(func() {
+			expect, _ := e.EvalBool()
+			for _, nf := range []expr.BoolExpr{nnf, cnf, dnf, expr.Flatten(e).(expr.BoolExpr)} {
+				if actual, err := nf.EvalBool(); err != nil || actual != expect {
+					t.Errorf("%v (a=%v b=%v c=%v d=%v): %v -> %v (expected %v)",
+						e, *a, *b, *c, *d, nf, actual, expect)
+				}
+			}
+		})
+	}
+}
+
+func TestNNF(t *testing.T) {
+	t.Parallel()
+	var x expr.Int
+	tcs := []struct {
+		e, expect expr.Expr
+	}{
+		{expr.Not{expr.Eq{&x, expr.Int(1)}}, expr.Ne{&x, expr.Int(1)}},
+		{expr.Not{expr.Gt{&x, expr.Int(1)}}, expr.Le{&x, expr.Int(1)}},
+		{expr.Not{expr.Le{&x, expr.Int(1)}}, expr.Gt{&x, expr.Int(1)}},
+		{
+			expr.Not{expr.Any{expr.Lt{&x, expr.Int(1)}, expr.Ne{&x, expr.Int(5)}}},
+			expr.All{expr.Ge{&x, expr.Int(1)}, expr.Eq{&x, expr.Int(5)}},
+		},
+		{expr.Not{expr.Not{expr.Ge{&x, expr.Int(1)}}}, expr.Ge{&x, expr.Int(1)}},
+		{expr.Not{expr.True}, expr.False},
+		{
+			expr.All{expr.Eq{&x, &x}, expr.All{expr.Lt{&x, &x}, expr.Any{expr.True}}},
+			expr.All{expr.Eq{&x, &x}, expr.All{expr.Lt{&x, &x}, expr.Any{expr.True}}},
+		},
+	}
+	for _, tc := range tcs {
+		if nnf := expr.NNF(tc.e); !expr.SameExpr(nnf, tc.expect) {
+			t.Errorf("%v -> %v (expected %v)", tc.e, nnf, tc.expect)
+		}
+	}
+	flat := expr.Flatten(expr.All{expr.Eq{&x, &x}, expr.All{expr.Lt{&x, &x}, expr.Any{expr.Any{expr.True}}}})
+	expect := expr.All{expr.Eq{&x, &x}, expr.Lt{&x, &x}, expr.Any{expr.True}}
+	if !expr.SameExpr(flat, expect) {
+		t.Errorf("flattened %v (expected %v)", flat, expect)
+	}
+	mapped := expr.Not{expr.All{expr.True, expr.Not{expr.False}}}.Copy(expr.NNF, expr.Flatten)
+	if !expr.SameExpr(mapped, expr.Any{expr.False, expr.False}) {
+		t.Errorf("Copy(NNF, Flatten) -> %v", mapped)
+	}
+}
+
+func TestNormalFormLimit(t *testing.T) {
+	t.Parallel()
+	vars, _ := boolVars(12)
+	// (v0 && v1) || (v2 && v3) || ... has 2 ** 6 clauses in CNF:
+	e := make(expr.Any, 0, 6)
+	for i := 0; i < len(vars); i += 2 {
+		e = append(e, expr.All{vars[i], vars[i+1]})
+	}
+	if _, err := expr.CNF(e, 64); err != nil {
+		t.Errorf("expected 64 clauses to be allowed: %v", err)
+	}
+	_, err := expr.CNF(e, 63)
+	var le *expr.LimitError
+	if !errors.As(err, &le) || le.Kind != expr.ClauseLimit {
+		t.Errorf("expected *LimitError but got %v", err)
+	}
+	if dnf, err := expr.DNF(e, 6); err != nil || !expr.SameExpr(dnf, e) {
+		t.Errorf("DNF(%v) -> %v (err: %v)", e, dnf, err)
+	}
+	if mapped := expr.CNFMapper(10)(expr.Not{e}); !expr.SameExpr(mapped, expr.NNF(expr.Not{e})) {
+		t.Errorf("expected the mapper to fall back to NNF but got %v", mapped)
+	}
+}
@@ -1,9 +1,9 @@
 package expr
 
 import (
+	"context"
+	"math"
 	"math/big"
-
-	"github.com/skillian/errors"
 )
 
 // Number is a specialization of Value for any rational number.
@@ -13,19 +13,264 @@ type Number interface {
 }
 
 // CmpNumbers is a specialization of the Cmp function that can handle any
-// value that implements Number.
+// value that implements Number.  Infinite floating point numbers are ordered
+// before and after all of the finite numbers and NaN is ordered before
+// everything except itself, like in sort.Float64Slice.
 func CmpNumbers(left, right Number) (result int) {
+	if l, r := floatRank(left), floatRank(right); l != 0 || r != 0 {
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	}
 	var rats [2]big.Rat
 	left.Rat(&rats[0])
 	right.Rat(&rats[1])
 	return (&rats[0]).Cmp(&rats[1])
 }
 
-func numberArithmeticHelper(left, right Number, bigRatFunc func(*big.Rat, *big.Rat, *big.Rat) *big.Rat) Number {
+// cmpNumbers compares left and right like CmpNumbers except that NaN is not
+// comparable to anything.
+func cmpNumbers(left, right Number) (int, error) {
+	if floatRank(left) == nanRank || floatRank(right) == nanRank {
+		return 0, notComparable(left, right, nil)
+	}
+	return CmpNumbers(left, right), nil
+}
+
+// nanRank is the floatRank of NaN.
+const nanRank = -2
+
+// floatRank gets the order of n relative to the finite numbers:  -1 for
+// negative infinity, 1 for positive infinity, nanRank for NaN and 0 for
+// everything else.
+func floatRank(n Number) int {
+	if !isFloat(n) {
+		return 0
+	}
+	switch f := floatOf(n); {
+	case math.IsNaN(f):
+		return nanRank
+	case math.IsInf(f, 0):
+		if f < 0 {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// numberRats sets rats to the values of left and right.  Infinite and NaN
+// floating point numbers have no rational value, so they result in a
+// *ConversionError.
+func numberRats(rats *[2]big.Rat, left, right Number) error {
+	for i, n := range [2]Number{left, right} {
+		if err := numberRat(&rats[i], n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numberRat sets r to the value of n like numberRats.
+func numberRat(r *big.Rat, n Number) error {
+	if floatRank(n) != 0 {
+		return conversionError(n, RationalType, nil)
+	}
+	n.Rat(r)
+	return nil
+}
+
+// numberArithmeticHelper performs op on left and right.  The result is a
+// Decimal if either operand is a Decimal, a Float64 if either operand is a
+// floating point number and a Rational otherwise.
+func numberArithmeticHelper(op arithOp, left, right Number) (Value, error) {
+	if _, _, ok := decimalOperands(left, right); ok {
+		return decimalArithmeticHelper(left, right, op.rat)
+	}
+	if isFloat(left) || isFloat(right) {
+		return Float64(op.float(floatOf(left), floatOf(right))), nil
+	}
+	var rats [2]big.Rat
+	if err := numberRats(&rats, left, right); err != nil {
+		return nil, err
+	}
+	return (*Rational)(op.rat(new(big.Rat), (&rats[0]), (&rats[1]))), nil
+}
+
+// numberDivideHelper divides left by right.  Division by zero results in a
+// *DivisionByZeroError unless one of the operands is a floating point number
+// and the context's DivisionMode is DivideByZeroIEEE.
+func numberDivideHelper(ctx context.Context, left, right Number) (Value, error) {
+	if _, _, ok := decimalOperands(left, right); !ok && (isFloat(left) || isFloat(right)) {
+		if err := checkFloatDivisor(ctx, left, right); err != nil {
+			return nil, err
+		}
+		return numberArithmeticHelper(opQuo, left, right)
+	}
+	var rats [2]big.Rat
+	if err := numberRats(&rats, left, right); err != nil {
+		return nil, err
+	}
+	if rats[1].Sign() != 0 {
+		return numberArithmeticHelper(opQuo, left, right)
+	}
+	if (isFloat(left) || isFloat(right)) && DivisionModeFromContext(ctx) == DivideByZeroIEEE {
+		return Float64(ieeeDivideByZero(float64(rats[0].Sign()), right)), nil
+	}
+	return nil, divisionByZero(left, right)
+}
+
+// numberModHelper gets the remainder of dividing left by right.  Integer
+// remainders are exact and have the sign of left like Go's % operator.
+// Floating point remainders are computed with math.Mod.
+func numberModHelper(ctx context.Context, left, right Number) (Value, error) {
+	if isFloat(left) || isFloat(right) {
+		divisor := floatOf(right)
+		if divisor == 0 && DivisionModeFromContext(ctx) != DivideByZeroIEEE {
+			return nil, divisionByZero(left, right)
+		}
+		return Float64(math.Mod(floatOf(left), divisor)), nil
+	}
+	var rats [2]big.Rat
+	left.Rat(&rats[0])
+	right.Rat(&rats[1])
+	if !rats[0].IsInt() || !rats[1].IsInt() {
+		return nil, typeMismatch("mod", left, right)
+	}
+	if rats[1].Sign() == 0 {
+		return nil, divisionByZero(left, right)
+	}
+	rem := new(big.Int).Rem(rats[0].Num(), rats[1].Num())
+	if mode := IntModeFromContext(ctx); mode != RationalInts && isFixedInt(left) && isFixedInt(right) {
+		return fixedIntResult(mode, left, right, rem)
+	}
+	return (*Rational)(new(big.Rat).SetInt(rem)), nil
+}
+
+// defaultMaxPowBits is the size limit of the results of Pow when
+// Limits.MaxRatBits is not set.
+const defaultMaxPowBits = 1 << 20
+
+// numberPowHelper raises left to the power of right.  Rational bases raised
+// to integer exponents are exact.  Other exponents are only supported with
+// floating point operands.
+func numberPowHelper(ctx context.Context, left, right Number) (Value, error) {
+	if isFloat(left) || isFloat(right) {
+		return Float64(math.Pow(floatOf(left), floatOf(right))), nil
+	}
 	var rats [2]big.Rat
 	left.Rat(&rats[0])
 	right.Rat(&rats[1])
-	return (*Rational)(bigRatFunc(new(big.Rat), (&rats[0]), (&rats[1])))
+	if !rats[1].IsInt() || !rats[1].Num().IsInt64() {
+		return nil, typeMismatch("raise", left, right)
+	}
+	exp := rats[1].Num().Int64()
+	base := &rats[0]
+	mode := IntModeFromContext(ctx)
+	if mode != RationalInts && exp >= 0 && isFixedInt(left) && isFixedInt(right) {
+		return fixedIntResult(mode, left, right, fixedIntPow(mode, base.Num(), exp))
+	}
+	if exp < 0 {
+		if base.Sign() == 0 {
+			return nil, divisionByZero(left, right)
+		}
+		base.Inv(base)
+		exp = -exp
+	}
+	// Powers of 0 and of 1 and -1 cannot grow.
+	if !base.IsInt() || base.Num().CmpAbs(big.NewInt(1)) > 0 {
+		st := evalStateFromContext(ctx)
+		limit := defaultMaxPowBits
+		if st != nil && st.MaxRatBits > 0 {
+			limit = st.MaxRatBits
+		}
+		bits := int64(base.Num().BitLen())
+		if !base.IsInt() {
+			bits += int64(base.Denom().BitLen())
+		}
+		if exp > int64(limit)/bits {
+			return nil, &LimitError{Kind: RatLimit, Max: limit, Expr: st.current(Pow{left, right})}
+		}
+	}
+	e := big.NewInt(exp)
+	num := new(big.Int).Exp(base.Num(), e, nil)
+	den := new(big.Int).Exp(base.Denom(), e, nil)
+	return (*Rational)(new(big.Rat).SetFrac(num, den)), nil
+}
+
+// fixedIntPow raises x to the power of exp for a fixed-width integer result.
+// Results that cannot fit into any fixed-width integer are not computed in
+// full:  When wrapping, only the low bits are computed and otherwise any
+// value out of range with the right sign is returned.
+func fixedIntPow(mode IntMode, x *big.Int, exp int64) *big.Int {
+	e := big.NewInt(exp)
+	if x.CmpAbs(big.NewInt(1)) <= 0 || exp < 128 {
+		return new(big.Int).Exp(x, e, nil)
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	if mode == WrapInts {
+		return new(big.Int).Exp(x, e, limit)
+	}
+	if x.Sign() < 0 && exp%2 == 1 {
+		return limit.Neg(limit)
+	}
+	return limit
+}
+
+// ieeeDivideByZero gets the IEEE 754 result of dividing a dividend with the
+// given sign by the zero divisor.
+func ieeeDivideByZero(sign float64, divisor Value) float64 {
+	var zero float64
+	switch divisor := divisor.(type) {
+	case Float32:
+		zero = float64(divisor)
+	case Float64:
+		zero = float64(divisor)
+	}
+	return sign / zero
+}
+
+// DivisionMode selects how division by zero is handled.
+type DivisionMode int
+
+const (
+	// DivideByZeroError makes division by zero result in a
+	// *DivisionByZeroError.
+	DivideByZeroError DivisionMode = iota
+
+	// DivideByZeroIEEE makes division by zero produce +Inf, -Inf or NaN
+	// like IEEE 754 when either operand is a floating point number.
+	// Dividing other numbers by zero still results in a
+	// *DivisionByZeroError.
+	DivideByZeroIEEE
+)
+
+type divisionModeKey struct{}
+
+// WithDivisionMode returns a copy of ctx whose evaluations handle division
+// by zero according to mode.
+func WithDivisionMode(ctx context.Context, mode DivisionMode) context.Context {
+	return context.WithValue(ctx, divisionModeKey{}, mode)
+}
+
+// DivisionModeFromContext gets the DivisionMode of the context.  The default
+// is DivideByZeroError.
+func DivisionModeFromContext(ctx context.Context) DivisionMode {
+	mode, _ := ctx.Value(divisionModeKey{}).(DivisionMode)
+	return mode
+}
+
+// isFloat returns true if the value is a floating point number.
+func isFloat(v Value) bool {
+	switch v.(type) {
+	case Float32, Float64:
+		return true
+	}
+	return false
 }
 
 // Rational wraps any rational numeric value.
@@ -64,9 +309,9 @@ func (r *Rational) copy() *Rational {
 // Cmp implements Cmper
 func (r *Rational) Cmp(v Value) (int, error) {
 	if n, ok := v.(Number); ok {
-		return CmpNumbers(r, n), nil
+		return cmpNumbers(r, n)
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", r, v)
+	return 0, notComparable(r, v, nil)
 }
 
 // Copy the expression.
@@ -91,38 +336,46 @@ func (r *Rational) EvalValue() (Value, error) {
 // EvalAdd adds v to r.
 func (r *Rational) EvalAdd(v Value) (Value, error) {
 	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(r, n, (*big.Rat).Add), nil
+		return numberArithmeticHelper(opAdd, r, n)
 	}
-	return nil, errors.Errorf("cannot add %v to %v", r, v)
+	return nil, typeMismatch("add", r, v)
 }
 
 // EvalSubtract subtracts v from r.
 func (r *Rational) EvalSubtract(v Value) (Value, error) {
 	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(r, n, (*big.Rat).Sub), nil
+		return numberArithmeticHelper(opSub, r, n)
 	}
-	return nil, errors.Errorf("cannot subtract %v from %v", r, v)
+	return nil, typeMismatch("subtract", r, v)
 }
 
 // EvalMultiply multiplies r by v.
 func (r *Rational) EvalMultiply(v Value) (Value, error) {
 	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(r, n, (*big.Rat).Mul), nil
+		return numberArithmeticHelper(opMul, r, n)
 	}
-	return nil, errors.Errorf("cannot multiply %v by %v", r, v)
+	return nil, typeMismatch("multiply", r, v)
 }
 
 // EvalDivide divides r by v.
 func (r *Rational) EvalDivide(v Value) (Value, error) {
+	return r.EvalDivideContext(context.Background(), v)
+}
+
+// EvalDivideContext divides r by v under ctx.
+func (r *Rational) EvalDivideContext(ctx context.Context, v Value) (Value, error) {
 	if n, ok := v.(Number); ok {
-		return numberArithmeticHelper(r, n, (*big.Rat).Quo), nil
+		return numberDivideHelper(ctx, r, n)
 	}
-	return nil, errors.Errorf("cannot divide %v by %v", r, v)
+	return nil, typeMismatch("divide", r, v)
 }
 
-// Interface can return an int64, uint64, float64, or *big.Rat.
+// Interface can return an int64, uint64, *big.Int, float64, or *big.Rat.
 func (r *Rational) Interface() interface{} {
-	return r.Value().Interface()
+	if v := r.Value(); v != Value(r) {
+		return v.Interface()
+	}
+	return new(big.Rat).Set((*big.Rat)(r))
 }
 
 // Type gets the Rational value's Type: RationalType.
@@ -130,8 +383,8 @@ func (r *Rational) Type() Type {
 	return RationalType
 }
 
-// Value attempts to simplify the Rational number into an Int, Int64, or
-// Float64.
+// Value attempts to simplify the Rational number into an Int64, Uint64,
+// BigInt or Float64.
 //
 // While the expr package is performing arithmetic on the objects, they stay as
 // Rationals in order to reduce the number of conversions. Once the last
@@ -147,6 +400,7 @@ func (r *Rational) Value() Value {
 		if n.IsUint64() {
 			return Uint64(n.Uint64())
 		}
+		return (*BigInt)(new(big.Int).Set(n))
 	}
 	if f, exact := br.Float64(); exact {
 		return Float64(f)
@@ -172,7 +426,7 @@ func (r *RationalVar) Copy(transformations ...Mapper) Expr {
 // Eval evaluates the RationalVar
 func (r *RationalVar) Eval() (interface{}, error) {
 	if r.value == nil {
-		return nil, errNilPtr
+		return nil, &NilVarError{}
 	}
 	return r.value.Eval()
 }
@@ -180,7 +434,7 @@ func (r *RationalVar) Eval() (interface{}, error) {
 // EvalValue evaluates the RationalVar to its Rational value.
 func (r *RationalVar) EvalValue() (Value, error) {
 	if r.value == nil {
-		return nil, errNilPtr
+		return nil, &NilVarError{}
 	}
 	return r.value.EvalValue()
 }
@@ -192,15 +446,14 @@ func (r *RationalVar) Value() Value {
 	return r.value
 }
 
-// SetValue sets r's value to v.
+// SetValue sets r's value to v converted into a Rational.
 func (r *RationalVar) SetValue(v Value) error {
-	if n, ok := v.(Number); ok {
-		var b big.Rat
-		n.Rat(&b)
-		((*big.Rat)(r.value)).Set(&b)
-		return nil
+	c, err := Convert(v, RationalType)
+	if err != nil {
+		return err
 	}
-	return errors.Errorf("cannot set %T value to %v", r, v)
+	r.value = c.(*Rational).copy()
+	return nil
 }
 
 // Rat stores r's rational value into the math/big.Rat rat.
@@ -0,0 +1,303 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func numericValues(n int64) []expr.Number {
+	return []expr.Number{
+		expr.Int(n),
+		expr.Int64(n),
+		expr.Uint64(n),
+		expr.Float32(n),
+		expr.Float64(n),
+		(*expr.Rational)(big.NewRat(n*3, 2)),
+	}
+}
+
+func isFloatNumber(n expr.Number) bool {
+	switch n.(type) {
+	case expr.Float32, expr.Float64:
+		return true
+	}
+	return false
+}
+
+func TestDivisionByZero(t *testing.T) {
+	t.Parallel()
+	ieee := expr.WithDivisionMode(context.Background(), expr.DivideByZeroIEEE)
+	for _, dividend := range numericValues(1) {
+		for _, divisor := range numericValues(0) {
+			diver, ok := dividend.(expr.ContextEvalDivider)
+			if !ok {
+				t.Fatalf("%T is not a ContextEvalDivider", dividend)
+			}
+			_, err := diver.EvalDivideContext(context.Background(), divisor)
+			var dz *expr.DivisionByZeroError
+			if !errors.As(err, &dz) {
+				t.Errorf("%v (%T) / %v (%T): expected *DivisionByZeroError but got %v",
+					dividend, dividend, divisor, divisor, err)
+			}
+			result, err := diver.EvalDivideContext(ieee, divisor)
+			if !isFloatNumber(dividend) && !isFloatNumber(divisor) {
+				if !errors.As(err, &dz) {
+					t.Errorf("%v (%T) / %v (%T): expected *DivisionByZeroError in IEEE mode but got %v",
+						dividend, dividend, divisor, divisor, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%v (%T) / %v (%T): %v", dividend, dividend, divisor, divisor, err)
+				continue
+			}
+			if f, ok := result.Interface().(float64); ok && math.IsInf(f, 1) {
+				continue
+			}
+			if f, ok := result.Interface().(float32); ok && math.IsInf(float64(f), 1) {
+				continue
+			}
+			t.Errorf("%v (%T) / %v (%T): expected +Inf but got %v (type: %T)",
+				dividend, dividend, divisor, divisor, result, result)
+		}
+	}
+}
+
+func TestDivisionByZeroExpr(t *testing.T) {
+	t.Parallel()
+	ieee := expr.WithDivisionMode(context.Background(), expr.DivideByZeroIEEE)
+	div := expr.Div{expr.Int(4), expr.Sub{expr.Int(2), expr.Int(2)}}
+	_, err := div.EvalValue()
+	var dz *expr.DivisionByZeroError
+	if !errors.As(err, &dz) {
+		t.Fatalf("expected *DivisionByZeroError but got %v", err)
+	}
+	if dz.Node != div {
+		t.Errorf("expected error node %v but got %v", div, dz.Node)
+	}
+	tcs := []struct {
+		expr.Div
+		check func(f float64) bool
+	}{
+		{expr.Div{expr.Int(-1), expr.Float64(0)}, func(f float64) bool { return math.IsInf(f, -1) }},
+		{expr.Div{expr.Int(1), expr.Float64(math.Copysign(0, -1))}, func(f float64) bool { return math.IsInf(f, -1) }},
+		{expr.Div{expr.Float64(0), expr.Int(0)}, math.IsNaN},
+		{expr.Div{expr.Float64(1), expr.Int(4)}, func(f float64) bool { return f == 0.25 }},
+	}
+	for _, tc := range tcs {
+		v, err := expr.EvalContext(ieee, tc.Div)
+		if err != nil {
+			t.Errorf("%v: %v", tc.Div, err)
+			continue
+		}
+		f, ok := v.Interface().(float64)
+		if !ok || !tc.check(f) {
+			t.Errorf("%v -> %v (type: %T)", tc.Div, v, v)
+		}
+	}
+}
+
+func TestFloatOperands(t *testing.T) {
+	t.Parallel()
+	inf, nan := math.Inf(1), math.NaN()
+	tcs := []struct {
+		left, right expr.Number
+		op          func(l, r expr.ValueExpr) expr.ValueExpr
+		expect      float64
+	}{
+		{expr.Int(1), expr.Float64(0.5), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Add{l, r} }, 1.5},
+		{expr.Int(1), expr.Float64(inf), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Add{l, r} }, inf},
+		{expr.Int64(2), expr.Float32(float32(nan)), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Mul{l, r} }, nan},
+		{expr.Int(1), expr.Float64(math.Inf(-1)), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Div{l, r} }, math.Copysign(0, -1)},
+		{(*expr.BigInt)(big.NewInt(3)), expr.Float64(inf), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Sub{l, r} }, math.Inf(-1)},
+		{(*expr.Rational)(big.NewRat(1, 4)), expr.Float32(0.5), func(l, r expr.ValueExpr) expr.ValueExpr { return expr.Mul{l, r} }, 0.125},
+	}
+	same := func(a, b float64) bool {
+		return a == b && math.Signbit(a) == math.Signbit(b) || math.IsNaN(a) && math.IsNaN(b)
+	}
+	for _, tc := range tcs {
+		e := tc.op(tc.left, tc.right)
+		v, err := e.EvalValue()
+		if f, ok := v.(expr.Float64); err != nil || !ok || !same(float64(f), tc.expect) {
+			t.Errorf("%v: expected %v but got %v (type: %T) (err: %v)", e, tc.expect, v, v, err)
+		}
+		// Swapping the operands must not change the type of the result:
+		e = tc.op(tc.right, tc.left)
+		if v, err := e.EvalValue(); err != nil || reflect.TypeOf(v) != reflect.TypeOf(expr.Float64(0)) {
+			t.Errorf("%v: expected a Float64 but got %v (type: %T) (err: %v)", e, v, v, err)
+		}
+	}
+	// Decimals take precedence over floats, but have no infinities:
+	for _, e := range []expr.ValueExpr{
+		expr.Sub{mustParseDecimal(t, "1.50"), expr.Float64(nan)},
+		expr.Sub{expr.Float64(nan), mustParseDecimal(t, "1.50")},
+		expr.Add{mustParseDecimal(t, "1.50"), expr.Float64(inf)},
+	} {
+		v, err := e.EvalValue()
+		var ce *expr.ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("%v: expected *ConversionError but got %v (err: %v)", e, v, err)
+		}
+	}
+}
+
+func TestNonFiniteComparisons(t *testing.T) {
+	t.Parallel()
+	inf, nan := expr.Float64(math.Inf(1)), expr.Float64(math.NaN())
+	ninf := expr.Float64(math.Inf(-1))
+	tcs := []struct {
+		expr.Expr
+		expect interface{}
+	}{
+		{expr.Gt{inf, expr.Int(5)}, true},
+		{expr.Gt{inf, expr.Float64(math.MaxFloat64)}, true},
+		{expr.Lt{expr.Int(5), inf}, true},
+		{expr.Eq{inf, expr.Int(0)}, false},
+		{expr.Eq{inf, inf}, true},
+		{expr.Eq{inf, expr.Float32(float32(math.Inf(1)))}, true},
+		{expr.Lt{ninf, expr.Float64(-1)}, true},
+		{expr.Lt{ninf, inf}, true},
+		{expr.Gt{(*expr.BigInt)(new(big.Int).Lsh(big.NewInt(1), 2000)), ninf}, true},
+		{expr.Lt{(*expr.BigInt)(new(big.Int).Lsh(big.NewInt(1), 2000)), inf}, true},
+		{expr.Eq{ninf, expr.Int(0)}, false},
+	}
+	for _, tc := range tcs {
+		testHelper(t, tc.Expr, tc.expect)
+	}
+	for _, e := range []expr.BoolExpr{
+		expr.Eq{nan, nan},
+		expr.Eq{nan, expr.Int(0)},
+		expr.Lt{expr.Int(0), nan},
+		expr.Gt{expr.Float32(float32(math.NaN())), expr.Float64(1)},
+		expr.Ne{expr.Int64(1), nan},
+	} {
+		var nc *expr.NotComparableError
+		if b, err := e.EvalBool(); !errors.As(err, &nc) {
+			t.Errorf("%v: expected *NotComparableError but got %v (err: %v)", e, b, err)
+		}
+	}
+	if expr.CmpNumbers(nan, expr.Int(0)) != -1 || expr.CmpNumbers(nan, nan) != 0 || expr.CmpNumbers(inf, nan) != 1 {
+		t.Errorf("expected NaN to be ordered before other numbers")
+	}
+}
+
+func TestIntMode(t *testing.T) {
+	t.Parallel()
+	maxInt64 := expr.Int64(math.MaxInt64)
+	minInt64 := expr.Int64(math.MinInt64)
+	tcs := []struct {
+		mode expr.IntMode
+		expr.ValueExpr
+		expect   expr.Value
+		overflow bool
+	}{
+		{expr.WrapInts, expr.Add{maxInt64, expr.Int64(1)}, minInt64, false},
+		{expr.SaturateInts, expr.Add{maxInt64, expr.Int64(1)}, maxInt64, false},
+		{expr.CheckedInts, expr.Add{maxInt64, expr.Int64(1)}, nil, true},
+		{expr.WrapInts, expr.Sub{expr.Uint64(0), expr.Uint64(1)}, expr.Uint64(math.MaxUint64), false},
+		{expr.SaturateInts, expr.Sub{expr.Uint64(0), expr.Uint64(1)}, expr.Uint64(0), false},
+		{expr.CheckedInts, expr.Sub{expr.Uint64(0), expr.Uint64(1)}, nil, true},
+		{expr.WrapInts, expr.Div{minInt64, expr.Int64(-1)}, minInt64, false},
+		{expr.CheckedInts, expr.Div{minInt64, expr.Int64(-1)}, nil, true},
+		{expr.CheckedInts, expr.Div{expr.Int(7), expr.Int(2)}, expr.Int(3), false},
+		{expr.CheckedInts, expr.Div{expr.Int(-7), expr.Int(2)}, expr.Int(-3), false},
+		{expr.CheckedInts, expr.Mul{expr.Int(6), expr.Int64(7)}, expr.Int(42), false},
+		{expr.WrapInts, expr.Mul{expr.Int64(1 << 32), expr.Int64(1 << 32)}, expr.Int64(0), false},
+	}
+	for _, tc := range tcs {
+		ctx := expr.WithIntMode(context.Background(), tc.mode)
+		v, err := expr.EvalContext(ctx, tc.ValueExpr)
+		if tc.overflow {
+			var oe *expr.OverflowError
+			if !errors.As(err, &oe) {
+				t.Errorf("%v: expected *OverflowError but got %v (result: %v)", tc.ValueExpr, err, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tc.ValueExpr, err)
+			continue
+		}
+		if v != tc.expect {
+			t.Errorf("%v -> %v (type: %T) (expected %v (type: %T))",
+				tc.ValueExpr, v, v, tc.expect, tc.expect)
+		}
+	}
+}
+
+func TestValueOfNumericTypes(t *testing.T) {
+	t.Parallel()
+	var i32 int32 = 5
+	tcs := []struct {
+		goValue interface{}
+		value   expr.Value
+	}{
+		{int8(5), expr.Int8(5)},
+		{int16(5), expr.Int16(5)},
+		{int32(5), expr.Int32(5)},
+		{int64(5), expr.Int64(5)},
+		{uint(5), expr.Uint(5)},
+		{uint8(5), expr.Uint8(5)},
+		{uint16(5), expr.Uint16(5)},
+		{uint32(5), expr.Uint32(5)},
+		{uint64(5), expr.Uint64(5)},
+		{complex64(5), expr.Complex64(5)},
+		{complex128(5), expr.Complex128(5)},
+	}
+	for _, tc := range tcs {
+		v := expr.ValueOf(tc.goValue)
+		if v != tc.value {
+			t.Errorf("ValueOf(%v (type: %T)) -> %v (type: %T)", tc.goValue, tc.goValue, v, v)
+			continue
+		}
+		if v.Interface() != tc.goValue {
+			t.Errorf("%v (type: %T).Interface() -> %v (type: %T)", v, v, v.Interface(), v.Interface())
+		}
+		sum, err := expr.Add{v, expr.Int(1)}.EvalValue()
+		if err != nil {
+			t.Errorf("%v + 1: %v", v, err)
+			continue
+		}
+		if eq, err := (expr.Eq{sum, expr.Int(6)}).EvalBool(); err != nil || !eq {
+			if c, ok := sum.(expr.Complex128); !ok || c != 6 {
+				t.Errorf("%v + 1 -> %v (type: %T)", v, sum, sum)
+			}
+		}
+	}
+	if p, ok := expr.ValueOf(&i32).(*expr.Int32); !ok || *p != 5 {
+		t.Errorf("ValueOf(*int32) -> %v (type: %T)", expr.ValueOf(&i32), expr.ValueOf(&i32))
+	}
+	for _, p := range []interface{}{(*int)(nil), (*int8)(nil), (*uint32)(nil), (*float64)(nil), (*complex64)(nil), (*string)(nil), (*expr.Int)(nil)} {
+		if v, ok := expr.ValueOf(p).(expr.Dynamic); !ok || v.Interface() != p {
+			t.Errorf("ValueOf(%T(nil)) -> %v (type: %T)", p, v, v)
+		}
+	}
+	fields := expr.ValueOf(struct {
+		A int32
+		B uint16
+	}{1, 2})
+	sum, err := expr.Add{
+		expr.Attr{ValueExpr: fields, Name: "A"},
+		expr.Attr{ValueExpr: fields, Name: "B"},
+	}.EvalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp, err := expr.Cmp(sum, expr.Int(3)); err != nil || cmp != 0 {
+		t.Errorf("A + B -> %v (type: %T)", sum, sum)
+	}
+	ctx := expr.WithIntMode(context.Background(), expr.WrapInts)
+	if v, err := expr.EvalContext(ctx, expr.Add{expr.Int8(127), expr.Int8(1)}); err != nil || v != expr.Int8(-128) {
+		t.Errorf("127 + 1 -> %v (type: %T) (err: %v)", v, v, err)
+	}
+	quo := expr.Div{expr.Complex128(2 + 4i), expr.Int(2)}
+	if v, err := quo.EvalValue(); err != nil || v != expr.Complex128(1+2i) {
+		t.Errorf("(2+4i) / 2 -> %v (type: %T) (err: %v)", v, v, err)
+	}
+}
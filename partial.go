@@ -0,0 +1,109 @@
+package expr
+
+import "context"
+
+// Bindings are the known values of variables.
+type Bindings map[Var]Value
+
+// Bind creates Bindings of the variables to their current values.
+func Bind(vars ...Var) Bindings {
+	b := make(Bindings, len(vars))
+	for _, v := range vars {
+		b[v] = v.Value()
+	}
+	return b
+}
+
+// PartialEval evaluates the parts of e that only depend on constants and the
+// bound variables and returns the residual expression that still has to be
+// evaluated once the rest of the variables are known.  If every variable in e
+// is bound, the result is a Value.
+//
+// Operands of All and Any that are known to be true or false are removed or
+// decide the result.  Subexpressions whose evaluation fails are left in the
+// residual expression so that their errors are returned when it is
+// evaluated, unless an All or Any is decided by one of its other operands.
+func PartialEval(e Expr, bindings Bindings) Expr {
+	return PartialEvalContext(context.Background(), e, bindings)
+}
+
+// PartialEvalContext partially evaluates e like PartialEval and evaluates
+// the known subexpressions under ctx.
+func PartialEvalContext(ctx context.Context, e Expr, bindings Bindings) Expr {
+	return partialEvaluator{ctx, bindings}.eval(e)
+}
+
+type partialEvaluator struct {
+	ctx      context.Context
+	bindings Bindings
+}
+
+func (p partialEvaluator) eval(e Expr) Expr {
+	if v, ok := e.(Var); ok {
+		if value, ok := p.bindings[v]; ok {
+			return value
+		}
+		return e
+	}
+	operands := Operands(e)
+	if IsConst(e) || len(operands) == 0 {
+		return e
+	}
+	known := true
+	for i, operand := range operands {
+		operands[i] = p.eval(operand)
+		known = known && IsConst(operands[i])
+	}
+	switch e.(type) {
+	case All:
+		return p.multary(e, operands, false)
+	case Any:
+		return p.multary(e, operands, true)
+	}
+	residual, err := WithOperands(e, operands)
+	if err != nil {
+		return e
+	}
+	if !known {
+		return residual
+	}
+	ve, ok := residual.(ValueExpr)
+	if !ok {
+		return residual
+	}
+	v, err := EvalContext(p.ctx, ve)
+	if err != nil {
+		return residual
+	}
+	return v
+}
+
+// multary simplifies an All (when decisive is false) or an Any (when
+// decisive is true) with partially evaluated operands.  Known operands equal
+// to decisive decide the result and the rest are dropped.
+func (p partialEvaluator) multary(e Expr, operands []Expr, decisive bool) Expr {
+	residual := make([]BoolExpr, 0, len(operands))
+	for _, operand := range operands {
+		if IsConst(operand) {
+			if Truthy(operand) == decisive {
+				return Bool(decisive)
+			}
+			continue
+		}
+		be, ok := operand.(BoolExpr)
+		if !ok {
+			return e
+		}
+		residual = append(residual, be)
+	}
+	switch len(residual) {
+	case 0:
+		return Bool(!decisive)
+	case 1:
+		return residual[0]
+	}
+	if decisive {
+		return Any(residual)
+	}
+	return All(residual)
+}
@@ -0,0 +1,107 @@
+package expr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestPartialEval(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	x = 3
+	bound := expr.Bind(&x)
+	tcs := []struct {
+		e      expr.Expr
+		expect expr.Expr
+	}{
+		{expr.All{expr.Gt{&x, expr.Int(1)}, expr.Lt{&y, expr.Int(10)}}, expr.Lt{&y, expr.Int(10)}},
+		{expr.All{expr.Lt{&x, expr.Int(1)}, expr.Lt{&y, expr.Int(10)}}, expr.False},
+		{expr.Any{expr.Lt{&y, expr.Int(10)}, expr.Gt{&x, expr.Int(1)}}, expr.True},
+		{
+			expr.Any{expr.Lt{&y, expr.Int(0)}, expr.Lt{&x, expr.Int(1)}, expr.Gt{&y, expr.Int(20)}},
+			expr.Any{expr.Lt{&y, expr.Int(0)}, expr.Gt{&y, expr.Int(20)}},
+		},
+		{expr.All{expr.Gt{&x, expr.Int(1)}, expr.Not{expr.Lt{&x, expr.Int(0)}}}, expr.True},
+		{expr.Eq{expr.Add{&x, &y}, expr.Int(4)}, expr.Eq{expr.Add{expr.Int(3), &y}, expr.Int(4)}},
+		{expr.Eq{expr.Add{&x, expr.Int(1)}, expr.Int(4)}, expr.True},
+	}
+	for _, tc := range tcs {
+		residual := expr.PartialEval(tc.e, bound)
+		if !expr.SameExpr(residual, tc.expect) {
+			t.Errorf("%v -> %v (expected %v)", tc.e, residual, tc.expect)
+		}
+	}
+	e := expr.Add{expr.Mul{&x, expr.Int(2)}, expr.Div{&y, &x}}
+	residual := expr.PartialEval(e, bound).(expr.ValueExpr)
+	for _, v := range []expr.Int{-7, 0, 6, 100} {
+		y = v
+		expect, err := e.EvalValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		x = 5
+		actual, err := residual.EvalValue()
+		x = 3
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eq, err := expr.EqualValues(actual, expect); err != nil || !eq {
+			t.Errorf("y = %v: %v -> %v (expected %v)", v, residual, actual, expect)
+		}
+	}
+	failing := expr.Add{expr.Div{&x, expr.Int(0)}, &y}
+	residual = expr.PartialEval(failing, bound).(expr.ValueExpr)
+	var dz *expr.DivisionByZeroError
+	if _, err := residual.EvalValue(); !errors.As(err, &dz) {
+		t.Errorf("%v: expected *DivisionByZeroError but got %v", residual, err)
+	}
+	if v := expr.PartialEval(e, expr.Bind(&x, &y)); !expr.IsConst(v) {
+		t.Errorf("expected a value when all variables are bound but got %v", v)
+	}
+}
+
+func TestWithOperands(t *testing.T) {
+	t.Parallel()
+	var x expr.Int
+	attr := expr.Attr{ValueExpr: expr.ValueOf(struct{ A int }{1}), Name: "A"}
+	tcs := []struct {
+		e        expr.Expr
+		operands []expr.Expr
+		expect   expr.Expr
+	}{
+		{expr.Add{&x, expr.Int(1)}, []expr.Expr{expr.Int(2), &x}, expr.Add{expr.Int(2), &x}},
+		{expr.Not{expr.True}, []expr.Expr{expr.Eq{&x, expr.Int(1)}}, expr.Not{expr.Eq{&x, expr.Int(1)}}},
+		{expr.All{expr.True, expr.False}, []expr.Expr{expr.False, expr.True}, expr.All{expr.False, expr.True}},
+		{attr, []expr.Expr{expr.ValueOf(struct{ A int }{2})}, expr.Attr{ValueExpr: expr.ValueOf(struct{ A int }{2}), Name: "A"}},
+		{expr.Set{expr.Int(1)}, []expr.Expr{&x}, expr.Set{&x}},
+	}
+	for _, tc := range tcs {
+		e, err := expr.WithOperands(tc.e, tc.operands)
+		if err != nil {
+			t.Errorf("%v: %v", tc.e, err)
+			continue
+		}
+		if !expr.SameExpr(e, tc.expect) {
+			t.Errorf("%v -> %v (expected %v)", tc.e, e, tc.expect)
+		}
+	}
+	if _, err := expr.WithOperands(expr.Not{expr.True}, []expr.Expr{expr.Add{&x, &x}}); err == nil {
+		t.Errorf("expected a non-BoolExpr operand of Not to fail")
+	}
+	if _, err := expr.WithOperands(expr.Add{&x, &x}, []expr.Expr{&x}); err == nil {
+		t.Errorf("expected the wrong number of operands to fail")
+	}
+	count := 0
+	countEq := func(e expr.Expr) expr.Expr {
+		if _, ok := e.(expr.Eq); ok {
+			count++
+		}
+		return e
+	}
+	expr.Any{expr.All{expr.Eq{&x, &x}}, expr.Eq{&x, &x}}.Copy(countEq)
+	if count != 2 {
+		t.Errorf("expected Copy to map 2 Eq expressions but mapped %d", count)
+	}
+}
@@ -0,0 +1,191 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Policy restricts which types, struct fields and methods can be accessed
+// through attributes during a context-aware evaluation.  A nil *Policy
+// allows everything.
+type Policy struct {
+	// DefaultDeny rejects any type or member that is not explicitly
+	// allowed.  When false, anything not explicitly denied is allowed.
+	DefaultDeny bool
+
+	// DisableMethods prevents Dynamic values from invoking methods to get
+	// attributes.  Only struct fields can be accessed.
+	DisableMethods bool
+
+	// Tag is the struct tag key that is checked on struct fields.  A field
+	// tagged with `<Tag>:"-"` is denied and a field tagged with
+	// `<Tag>:"allow"` is allowed.  If Tag is empty, struct tags are not
+	// checked.
+	Tag string
+
+	types   map[reflect.Type]access
+	members map[member]access
+}
+
+// access is the result of looking a type or member up in a Policy.
+type access int
+
+const (
+	unspecified access = iota
+	allowed
+	denied
+)
+
+type member struct {
+	reflect.Type
+	name string
+}
+
+// NewPolicy creates an empty Policy.
+func NewPolicy() *Policy {
+	return &Policy{
+		types:   make(map[reflect.Type]access),
+		members: make(map[member]access),
+	}
+}
+
+// AllowType allows access to the attributes of values of the given types.
+func (p *Policy) AllowType(types ...reflect.Type) *Policy {
+	return p.setTypes(allowed, types)
+}
+
+// DenyType denies access to the attributes of values of the given types.
+func (p *Policy) DenyType(types ...reflect.Type) *Policy {
+	return p.setTypes(denied, types)
+}
+
+// AllowMember allows access to the named fields or methods of t.
+func (p *Policy) AllowMember(t reflect.Type, names ...string) *Policy {
+	return p.setMembers(allowed, t, names)
+}
+
+// DenyMember denies access to the named fields or methods of t.
+func (p *Policy) DenyMember(t reflect.Type, names ...string) *Policy {
+	return p.setMembers(denied, t, names)
+}
+
+func (p *Policy) setTypes(a access, types []reflect.Type) *Policy {
+	if p.types == nil {
+		p.types = make(map[reflect.Type]access)
+	}
+	for _, t := range types {
+		p.types[indirectType(t)] = a
+	}
+	return p
+}
+
+func (p *Policy) setMembers(a access, t reflect.Type, names []string) *Policy {
+	if p.members == nil {
+		p.members = make(map[member]access)
+	}
+	for _, name := range names {
+		p.members[member{indirectType(t), name}] = a
+	}
+	return p
+}
+
+// checkMember checks if the named attribute of a value of type t can be
+// accessed.
+func (p *Policy) checkMember(t reflect.Type, name string) error {
+	return p.check(t, name, unspecified, false)
+}
+
+// checkField checks if the struct field f of t can be accessed.
+func (p *Policy) checkField(t reflect.Type, f reflect.StructField) error {
+	tagged := unspecified
+	if p != nil && p.Tag != "" {
+		switch f.Tag.Get(p.Tag) {
+		case "-":
+			tagged = denied
+		case "allow":
+			tagged = allowed
+		}
+	}
+	return p.check(t, f.Name, tagged, false)
+}
+
+// checkMethod checks if the named method of t can be called.
+func (p *Policy) checkMethod(t reflect.Type, name string) error {
+	if p != nil && p.DisableMethods {
+		return &AccessError{Type: t, Name: name, Method: true}
+	}
+	return p.check(t, name, unspecified, true)
+}
+
+func (p *Policy) check(t reflect.Type, name string, tagged access, method bool) error {
+	if p == nil {
+		return nil
+	}
+	it := indirectType(t)
+	a := p.members[member{it, name}]
+	if a == unspecified {
+		a = tagged
+	}
+	if a == unspecified && p.types[it] == denied {
+		a = denied
+	}
+	if a == unspecified && p.types[it] == allowed {
+		a = allowed
+	}
+	if a == denied || (a == unspecified && p.DefaultDeny) {
+		return &AccessError{Type: t, Name: name, Method: method}
+	}
+	return nil
+}
+
+// AccessError is returned when a Policy rejects access to an attribute.
+type AccessError struct {
+	// Path is the attribute expression that was rejected.
+	Path string
+
+	// Type is the type of the value whose attribute was rejected.
+	Type reflect.Type
+
+	// Name is the name of the rejected field or method.
+	Name string
+
+	// Method is true if Name refers to a method.
+	Method bool
+}
+
+func (e *AccessError) Error() string {
+	kind := "attribute"
+	if e.Method {
+		kind = "method"
+	}
+	path := e.Path
+	if path == "" {
+		path = e.Name
+	}
+	return fmt.Sprintf(
+		"access to %s %q of %v denied by policy (path: %s)",
+		kind, e.Name, e.Type, path)
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a copy of ctx whose evaluations enforce the given
+// Policy.
+func WithPolicy(ctx context.Context, p *Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// PolicyFromContext gets the Policy associated with the context or nil if
+// there isn't one.
+func PolicyFromContext(ctx context.Context) *Policy {
+	p, _ := ctx.Value(policyKey{}).(*Policy)
+	return p
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,73 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+type policyAccount struct {
+	Owner  string
+	Secret string `expr:"-"`
+}
+
+func (a policyAccount) Delete() string {
+	return "deleted " + a.Owner
+}
+
+type policyUser struct {
+	Name    string
+	Account policyAccount
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+	user := expr.ValueOf(policyUser{
+		Name:    "Sean",
+		Account: policyAccount{Owner: "Sean", Secret: "hunter2"},
+	})
+	accountType := reflect.TypeOf(policyAccount{})
+	tcs := []struct {
+		name   string
+		policy *expr.Policy
+		expr.ValueExpr
+		denied string
+	}{
+		{"no policy", nil, expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Delete"}, ""},
+		{"tag", &expr.Policy{Tag: "expr"}, expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Secret"}, "Secret"},
+		{"tag allows others", &expr.Policy{Tag: "expr"}, expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Owner"}, ""},
+		{"methods disabled", &expr.Policy{DisableMethods: true}, expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Delete"}, "Delete"},
+		{"deny member", expr.NewPolicy().DenyMember(accountType, "Delete"), expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Delete"}, "Delete"},
+		{"deny type", expr.NewPolicy().DenyType(accountType), expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Owner"}, "Owner"},
+		{"default deny", &expr.Policy{DefaultDeny: true}, expr.Attr{ValueExpr: user, Name: "Name"}, "Name"},
+		{"default deny allowed type", (&expr.Policy{DefaultDeny: true}).AllowType(reflect.TypeOf(policyUser{})), expr.Attr{ValueExpr: user, Name: "Name"}, ""},
+		{"allow member overrides type", expr.NewPolicy().DenyType(accountType).AllowMember(accountType, "Owner"), expr.Attr{ValueExpr: expr.Attr{ValueExpr: user, Name: "Account"}, Name: "Owner"}, ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := expr.WithPolicy(context.Background(), tc.policy)
+			value, err := expr.EvalContext(ctx, tc.ValueExpr)
+			if tc.denied == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Log(tc.ValueExpr, "->", value)
+				return
+			}
+			var ae *expr.AccessError
+			if !errors.As(err, &ae) {
+				t.Fatalf("expected *AccessError but got %v (value: %v)", err, value)
+			}
+			if ae.Name != tc.denied {
+				t.Errorf("expected %q to be denied but got %q", tc.denied, ae.Name)
+			}
+			if ae.Path == "" {
+				t.Error("access error has no path")
+			}
+			t.Log(ae)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package expr
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Range is an interval of numbers.  The zero Range is unbounded.
+type Range struct {
+	Lo, Hi Bound
+}
+
+// Bound is an endpoint of a Range.
+type Bound struct {
+	// Value of the bound.  If it is nil, the Range is unbounded in the
+	// bound's direction.  Ranges produced by AnalyzeRanges have *Rational
+	// bounds.
+	Value Number
+
+	// Open is true if the Value itself is not in the Range.
+	Open bool
+}
+
+// NewRange creates a closed Range from lo to hi.
+func NewRange(lo, hi Number) Range {
+	return Range{Lo: Bound{Value: lo}, Hi: Bound{Value: hi}}
+}
+
+// Contains checks if v is in the range.
+func (r Range) Contains(v Number) bool {
+	var x big.Rat
+	if !ratOf(v, &x) {
+		return false
+	}
+	p := endpoint{r: &x}
+	return r.extent().contains(p)
+}
+
+// String represents the range in interval notation, e.g. "[0, 1/2)".
+func (r Range) String() string {
+	var sb strings.Builder
+	iv := r.extent()
+	if iv.lo.open {
+		sb.WriteByte('(')
+	} else {
+		sb.WriteByte('[')
+	}
+	sb.WriteString(iv.lo.String())
+	sb.WriteString(", ")
+	sb.WriteString(iv.hi.String())
+	if iv.hi.open {
+		sb.WriteByte(')')
+	} else {
+		sb.WriteByte(']')
+	}
+	return sb.String()
+}
+
+// Ranges are the ranges of the values of variables.
+type Ranges map[Var]Range
+
+// RangeNode is the result of the range analysis of an expression.
+type RangeNode struct {
+	// Expr is the analyzed expression.
+	Expr Expr
+
+	// Range holds every value that a numeric expression can evaluate to.
+	// Expressions that aren't numeric or that can't be analyzed have
+	// unbounded ranges.
+	Range Range
+
+	// CanBeTrue and CanBeFalse are the possible results of a boolean
+	// expression.
+	CanBeTrue, CanBeFalse bool
+
+	// DivByZero is true if the expression is a Div whose divisor can be
+	// zero.  The range of such a Div is unbounded.
+	DivByZero bool
+
+	// Operands are the nodes of the expression's operands.
+	Operands []*RangeNode
+}
+
+// DivByZeros gets the nodes of the divisions that can divide by zero in the
+// order they appear in the expression.
+func (n *RangeNode) DivByZeros() []*RangeNode {
+	var divs []*RangeNode
+	if n.DivByZero {
+		divs = append(divs, n)
+	}
+	for _, o := range n.Operands {
+		divs = append(divs, o.DivByZeros()...)
+	}
+	return divs
+}
+
+// AnalyzeRanges determines the range of values that every numeric
+// subexpression of e can evaluate to and the possible results of its
+// comparisons when its variables are within the given ranges.  Variables
+// without a range are unbounded.
+//
+// Add, Sub, Mul and Div are analyzed with exact rational arithmetic, so the
+// analysis doesn't account for integer truncation or floating point
+// rounding.  Each operand is analyzed independently, so the ranges are
+// conservative when a variable appears more than once (e.g. x - x ranges
+// from -2 to 2 when x ranges from 0 to 1).
+func AnalyzeRanges(e Expr, ranges Ranges) *RangeNode {
+	n := &RangeNode{Expr: e}
+	if v, ok := e.(Var); ok {
+		n.Range = ranges[v]
+		n.CanBeTrue, n.CanBeFalse = true, true
+		return n
+	}
+	if IsConst(e) {
+		switch v := e.(type) {
+		case Bool:
+			n.CanBeTrue, n.CanBeFalse = bool(v), !bool(v)
+		case Number:
+			var x big.Rat
+			if ratOf(v, &x) {
+				b := Bound{Value: (*Rational)(&x)}
+				n.Range = Range{Lo: b, Hi: b}
+			}
+		}
+		return n
+	}
+	for _, operand := range Operands(e) {
+		n.Operands = append(n.Operands, AnalyzeRanges(operand, ranges))
+	}
+	switch e.(type) {
+	case Add, Sub, Mul, Div:
+		l, r := n.Operands[0].Range.extent(), n.Operands[1].Range.extent()
+		var iv extent
+		switch e.(type) {
+		case Add:
+			iv = l.add(r)
+		case Sub:
+			iv = l.add(r.neg())
+		case Mul:
+			iv = l.mul(r)
+		case Div:
+			if r.contains(endpoint{r: new(big.Rat)}) {
+				n.DivByZero = true
+				return n
+			}
+			iv = l.mul(r.recip())
+		}
+		n.Range = iv.Range()
+	case Eq, Ne, Lt, Le, Gt, Ge:
+		d := n.Operands[0].Range.extent().add(n.Operands[1].Range.extent().neg())
+		neg, zero, pos := d.signs()
+		switch e.(type) {
+		case Eq:
+			n.CanBeTrue, n.CanBeFalse = zero, neg || pos
+		case Ne:
+			n.CanBeTrue, n.CanBeFalse = neg || pos, zero
+		case Lt:
+			n.CanBeTrue, n.CanBeFalse = neg, zero || pos
+		case Le:
+			n.CanBeTrue, n.CanBeFalse = neg || zero, pos
+		case Gt:
+			n.CanBeTrue, n.CanBeFalse = pos, neg || zero
+		case Ge:
+			n.CanBeTrue, n.CanBeFalse = pos || zero, neg
+		}
+	case All:
+		n.CanBeTrue, n.CanBeFalse = true, false
+		for _, o := range n.Operands {
+			n.CanBeTrue = n.CanBeTrue && o.CanBeTrue
+			n.CanBeFalse = n.CanBeFalse || o.CanBeFalse
+		}
+	case Any:
+		n.CanBeTrue, n.CanBeFalse = false, true
+		for _, o := range n.Operands {
+			n.CanBeTrue = n.CanBeTrue || o.CanBeTrue
+			n.CanBeFalse = n.CanBeFalse && o.CanBeFalse
+		}
+	case Not:
+		n.CanBeTrue, n.CanBeFalse = n.Operands[0].CanBeFalse, n.Operands[0].CanBeTrue
+	default:
+		n.CanBeTrue, n.CanBeFalse = true, true
+	}
+	return n
+}
+
+// endpoint is a bound of an extent.  Infinite endpoints have a nil r and
+// an inf of -1 or 1.
+type endpoint struct {
+	r    *big.Rat
+	inf  int
+	open bool
+}
+
+func (p endpoint) sign() int {
+	if p.r == nil {
+		return p.inf
+	}
+	return p.r.Sign()
+}
+
+func (p endpoint) cmp(q endpoint) int {
+	if p.r == nil || q.r == nil {
+		pi, qi := p.inf, q.inf
+		switch {
+		case pi < qi:
+			return -1
+		case pi > qi:
+			return 1
+		}
+		return 0
+	}
+	return p.r.Cmp(q.r)
+}
+
+func (p endpoint) add(q endpoint) endpoint {
+	if p.r == nil {
+		return p
+	}
+	if q.r == nil {
+		return q
+	}
+	return endpoint{r: new(big.Rat).Add(p.r, q.r), open: p.open || q.open}
+}
+
+func (p endpoint) neg() endpoint {
+	if p.r == nil {
+		return endpoint{inf: -p.inf, open: true}
+	}
+	return endpoint{r: new(big.Rat).Neg(p.r), open: p.open}
+}
+
+// mul multiplies the endpoints.  Because the values in an extent are
+// finite, zero times infinity is zero, which is only in the extent if the
+// zero is.
+func (p endpoint) mul(q endpoint) endpoint {
+	pz, qz := p.sign() == 0, q.sign() == 0
+	if pz || qz {
+		return endpoint{
+			r:    new(big.Rat),
+			open: !(pz && !p.open) && !(qz && !q.open),
+		}
+	}
+	if p.r == nil || q.r == nil {
+		return endpoint{inf: p.sign() * q.sign(), open: true}
+	}
+	return endpoint{r: new(big.Rat).Mul(p.r, q.r), open: p.open || q.open}
+}
+
+// recip gets the reciprocal of an endpoint of an extent that doesn't
+// contain zero.  The reciprocal of an open zero endpoint is infinite in the
+// direction of inf.
+func (p endpoint) recip(inf int) endpoint {
+	switch {
+	case p.r == nil:
+		return endpoint{r: new(big.Rat), open: true}
+	case p.r.Sign() == 0:
+		return endpoint{inf: inf, open: true}
+	}
+	return endpoint{r: new(big.Rat).Inv(p.r), open: p.open}
+}
+
+func (p endpoint) String() string {
+	switch {
+	case p.inf < 0:
+		return "-inf"
+	case p.inf > 0:
+		return "+inf"
+	}
+	return p.r.RatString()
+}
+
+// extent is a Range with endpoints that are easier to compute with.
+type extent struct {
+	lo, hi endpoint
+}
+
+// extent gets the endpoints of the range.
+func (r Range) extent() extent {
+	iv := extent{
+		lo: endpoint{inf: -1, open: true},
+		hi: endpoint{inf: 1, open: true},
+	}
+	var x big.Rat
+	if r.Lo.Value != nil && ratOf(r.Lo.Value, &x) {
+		iv.lo = endpoint{r: new(big.Rat).Set(&x), open: r.Lo.Open}
+	}
+	if r.Hi.Value != nil && ratOf(r.Hi.Value, &x) {
+		iv.hi = endpoint{r: new(big.Rat).Set(&x), open: r.Hi.Open}
+	}
+	return iv
+}
+
+// Range gets the Range with the extent's endpoints.
+func (iv extent) Range() Range {
+	var r Range
+	if iv.lo.r != nil {
+		r.Lo = Bound{Value: (*Rational)(iv.lo.r), Open: iv.lo.open}
+	}
+	if iv.hi.r != nil {
+		r.Hi = Bound{Value: (*Rational)(iv.hi.r), Open: iv.hi.open}
+	}
+	return r
+}
+
+// contains checks if the extent contains the finite point p.
+func (iv extent) contains(p endpoint) bool {
+	if c := p.cmp(iv.lo); c < 0 || (c == 0 && iv.lo.open) {
+		return false
+	}
+	if c := p.cmp(iv.hi); c > 0 || (c == 0 && iv.hi.open) {
+		return false
+	}
+	return true
+}
+
+// signs checks if the extent has negative values, zero and positive
+// values.
+func (iv extent) signs() (neg, zero, pos bool) {
+	neg = iv.lo.sign() < 0
+	zero = iv.contains(endpoint{r: new(big.Rat)})
+	pos = iv.hi.sign() > 0
+	return
+}
+
+func (iv extent) add(o extent) extent {
+	return extent{lo: iv.lo.add(o.lo), hi: iv.hi.add(o.hi)}
+}
+
+func (iv extent) neg() extent {
+	return extent{lo: iv.hi.neg(), hi: iv.lo.neg()}
+}
+
+// mul multiplies the intervals.  The product's endpoints are the smallest
+// and largest products of their endpoints.  When the products are equal, the
+// closed one is used because its value is in the product.
+func (iv extent) mul(o extent) extent {
+	products := [4]endpoint{
+		iv.lo.mul(o.lo), iv.lo.mul(o.hi),
+		iv.hi.mul(o.lo), iv.hi.mul(o.hi),
+	}
+	result := extent{lo: products[0], hi: products[0]}
+	for _, p := range products[1:] {
+		if c := p.cmp(result.lo); c < 0 || (c == 0 && !p.open) {
+			result.lo = p
+		}
+		if c := p.cmp(result.hi); c > 0 || (c == 0 && !p.open) {
+			result.hi = p
+		}
+	}
+	return result
+}
+
+// recip gets the reciprocals of the values of an extent that doesn't
+// contain zero.
+func (iv extent) recip() extent {
+	return extent{lo: iv.hi.recip(-1), hi: iv.lo.recip(1)}
+}
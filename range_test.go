@@ -0,0 +1,133 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestAnalyzeRanges(t *testing.T) {
+	t.Parallel()
+	var x, y, z expr.Int
+	ranges := expr.Ranges{
+		&x: expr.NewRange(expr.Int(0), expr.Int(10)),
+		&y: {Lo: expr.Bound{Value: expr.Int(1)}, Hi: expr.Bound{Value: expr.Int(2), Open: true}},
+		&z: {Lo: expr.Bound{Value: expr.Int(-1)}},
+	}
+	tcs := []struct {
+		e      expr.Expr
+		expect string
+	}{
+		{&x, "[0, 10]"},
+		{&z, "[-1, +inf)"},
+		{expr.Int(3), "[3, 3]"},
+		{expr.String("3"), "(-inf, +inf)"},
+		{expr.Add{&x, &y}, "[1, 12)"},
+		{expr.Sub{&x, &y}, "(-2, 9]"},
+		{expr.Sub{&x, &x}, "[-10, 10]"},
+		{expr.Mul{&x, &y}, "[0, 20)"},
+		{expr.Mul{&y, expr.Int(-2)}, "(-4, -2]"},
+		{expr.Mul{&x, &z}, "[-10, +inf)"},
+		{expr.Mul{&y, &z}, "(-2, +inf)"},
+		{expr.Mul{&z, &z}, "(-inf, +inf)"},
+		{expr.Div{&x, &y}, "[0, 10]"},
+		{expr.Div{expr.Int(1), &y}, "(1/2, 1]"},
+		{expr.Div{expr.Int(1), expr.Add{&z, expr.Int(2)}}, "(0, 1]"},
+		{expr.Div{&y, &x}, "(-inf, +inf)"},
+		{expr.Add{expr.Mul{&x, expr.Float64(0.5)}, expr.Int(1)}, "[1, 6]"},
+		{expr.Mod{&x, &y}, "(-inf, +inf)"},
+	}
+	for _, tc := range tcs {
+		if actual := expr.AnalyzeRanges(tc.e, ranges).Range.String(); actual != tc.expect {
+			t.Errorf("%v: %v (expected %v)", tc.e, actual, tc.expect)
+		}
+	}
+}
+
+func TestAnalyzeRangesContains(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	ranges := expr.Ranges{
+		&x: expr.NewRange(expr.Int(-3), expr.Int(4)),
+		&y: {Lo: expr.Bound{Value: expr.Int(1), Open: true}, Hi: expr.Bound{Value: expr.Int(5)}},
+	}
+	es := []expr.ValueExpr{
+		expr.Add{&x, &y},
+		expr.Sub{&x, &y},
+		expr.Mul{&x, &y},
+		expr.Div{&x, &y},
+		expr.Div{expr.Mul{&x, &x}, expr.Sub{&y, expr.Int(7)}},
+	}
+	for _, e := range es {
+		r := expr.AnalyzeRanges(e, ranges).Range
+		for x = -3; x <= 4; x++ {
+			for y = 2; y <= 5; y++ {
+				v, err := e.EvalValue()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !r.Contains(v.(expr.Number)) {
+					t.Errorf("%v = %v with x=%v, y=%v is not in %v", e, v, x, y, r)
+				}
+			}
+		}
+	}
+}
+
+func TestAnalyzeRangesComparisons(t *testing.T) {
+	t.Parallel()
+	var price, discount, qty expr.Int
+	var b expr.Bool
+	ranges := expr.Ranges{
+		&price:    expr.NewRange(expr.Int(10), expr.Int(100)),
+		&discount: expr.NewRange(expr.Int(0), expr.Int(5)),
+		&qty:      expr.NewRange(expr.Int(0), expr.Int(5)),
+	}
+	total := expr.Mul{expr.Sub{&price, &discount}, &qty}
+	tcs := []struct {
+		e                     expr.BoolExpr
+		canBeTrue, canBeFalse bool
+	}{
+		{expr.Ge{total, expr.Int(0)}, true, false},
+		{expr.Lt{total, expr.Int(0)}, false, true},
+		{expr.Gt{total, expr.Int(0)}, true, true},
+		{expr.Le{total, expr.Int(500)}, true, false},
+		{expr.Eq{&price, expr.Int(5)}, false, true},
+		{expr.Ne{&price, expr.Int(5)}, true, false},
+		{expr.Eq{&price, expr.Int(10)}, true, true},
+		{expr.Gt{&price, &discount}, true, false},
+		{expr.Ge{&discount, &price}, false, true},
+		{expr.All{expr.Gt{&price, &discount}, expr.Ge{&qty, expr.Int(0)}}, true, false},
+		{expr.All{expr.Gt{&price, &discount}, &b}, true, true},
+		{expr.Any{expr.Lt{&price, expr.Int(0)}, expr.Gt{&qty, expr.Int(5)}}, false, true},
+		{expr.Not{expr.Lt{&price, expr.Int(0)}}, true, false},
+		{expr.Eq{expr.Int(1), expr.Int(1)}, true, false},
+	}
+	for _, tc := range tcs {
+		n := expr.AnalyzeRanges(tc.e, ranges)
+		if n.CanBeTrue != tc.canBeTrue || n.CanBeFalse != tc.canBeFalse {
+			t.Errorf("%v: can be true: %v, can be false: %v (expected %v, %v)",
+				tc.e, n.CanBeTrue, n.CanBeFalse, tc.canBeTrue, tc.canBeFalse)
+		}
+	}
+}
+
+func TestAnalyzeRangesDivByZero(t *testing.T) {
+	t.Parallel()
+	var x, y expr.Int
+	ranges := expr.Ranges{
+		&x: expr.NewRange(expr.Int(0), expr.Int(10)),
+		&y: {Lo: expr.Bound{Value: expr.Int(0), Open: true}},
+	}
+	safe := expr.Div{&x, &y}
+	unsafe := expr.Div{&y, expr.Sub{&x, expr.Int(5)}}
+	e := expr.Gt{expr.Add{safe, expr.Div{unsafe, &x}}, expr.Int(1)}
+	n := expr.AnalyzeRanges(e, ranges)
+	divs := n.DivByZeros()
+	if len(divs) != 2 || !expr.SameExpr(divs[0].Expr, expr.Div{unsafe, &x}) || !expr.SameExpr(divs[1].Expr, unsafe) {
+		t.Errorf("unexpected divisions by zero: %v", divs)
+	}
+	if r := n.Operands[0].Operands[0].Range.String(); r != "[0, +inf)" {
+		t.Errorf("%v: %v", safe, r)
+	}
+}
@@ -0,0 +1,237 @@
+package expr
+
+import (
+	"reflect"
+
+	"github.com/skillian/errors"
+)
+
+// Placeholder is a named hole in a pattern or template expression.  When a
+// pattern is matched against an expression, each Placeholder matches any
+// subexpression and captures it by name.  Placeholders can be used as the
+// operands of any expression, including Not, All and Any, but they cannot be
+// evaluated.
+type Placeholder string
+
+// Copy the placeholder.
+func (p Placeholder) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(p, transformations...)
+}
+
+// Eval always fails because placeholders have no value.
+func (p Placeholder) Eval() (interface{}, error) {
+	return nil, p.unbound()
+}
+
+// EvalValue always fails because placeholders have no value.
+func (p Placeholder) EvalValue() (Value, error) {
+	return nil, p.unbound()
+}
+
+// EvalBool always fails because placeholders have no value.
+func (p Placeholder) EvalBool() (bool, error) {
+	return false, p.unbound()
+}
+
+func (p Placeholder) unbound() error {
+	return errors.Errorf("cannot evaluate unbound placeholder %v", p)
+}
+
+// String represents the placeholder as its name prefixed with a "$".
+func (p Placeholder) String() string {
+	return "$" + string(p)
+}
+
+// Captures are the subexpressions captured by the placeholders of a pattern.
+type Captures map[Placeholder]Expr
+
+// Match checks if e has the same structure as the pattern and captures the
+// subexpressions of e that are in the places of the pattern's Placeholders.
+// A Placeholder that appears more than once in the pattern only matches
+// subexpressions that are the same according to SameExpr.  Everything else
+// in the pattern must be the same as in e, so Vars only match themselves and
+// Values only match Values of the same type (e.g. Int(0) doesn't match
+// Float64(0)).
+func Match(pattern, e Expr) (Captures, bool) {
+	c := make(Captures)
+	if !c.match(pattern, e) {
+		return nil, false
+	}
+	return c, true
+}
+
+func (c Captures) match(pattern, e Expr) bool {
+	if p, ok := pattern.(Placeholder); ok {
+		if captured, ok := c[p]; ok {
+			return SameExpr(captured, e)
+		}
+		c[p] = e
+		return true
+	}
+	patterns := Operands(pattern)
+	if len(patterns) == 0 {
+		return SameExpr(pattern, e)
+	}
+	if reflect.TypeOf(pattern) != reflect.TypeOf(e) {
+		return false
+	}
+	operands := Operands(e)
+	if len(operands) != len(patterns) {
+		return false
+	}
+	for i, operand := range operands {
+		if !c.match(patterns[i], operand) {
+			return false
+		}
+	}
+	// The operands match, but the parts of the expressions that aren't
+	// operands, like the names of Attrs, have to match too:
+	withOperands, err := WithOperands(pattern, operands)
+	return err == nil && SameExpr(withOperands, e)
+}
+
+// Substitute creates a copy of the template with its Placeholders replaced
+// by the captured expressions.  An error is returned if a Placeholder isn't
+// captured or if a captured expression can't be used as an operand in the
+// template (e.g. a non-BoolExpr in a Not).
+func Substitute(template Expr, c Captures) (Expr, error) {
+	if p, ok := template.(Placeholder); ok {
+		e, ok := c[p]
+		if !ok {
+			return nil, errors.Errorf("placeholder %v is not captured", p)
+		}
+		return e, nil
+	}
+	operands := Operands(template)
+	if len(operands) == 0 {
+		return template, nil
+	}
+	for i, operand := range operands {
+		e, err := Substitute(operand, c)
+		if err != nil {
+			return nil, err
+		}
+		operands[i] = e
+	}
+	return WithOperands(template, operands)
+}
+
+// RewriteRule replaces expressions that match its Pattern with its
+// Replacement.
+type RewriteRule struct {
+	// Pattern is matched against expressions with Match.
+	Pattern Expr
+
+	// Replacement is the template that the Pattern's captures are
+	// substituted into.
+	Replacement Expr
+
+	// When, if not nil, must be true for the captures or the rule doesn't
+	// apply.
+	When func(c Captures) bool
+}
+
+// apply applies the rule to e.
+func (r RewriteRule) apply(e Expr) (Expr, bool) {
+	c, ok := Match(r.Pattern, e)
+	if !ok || (r.When != nil && !r.When(c)) {
+		return e, false
+	}
+	replaced, err := Substitute(r.Replacement, c)
+	if err != nil {
+		return e, false
+	}
+	return replaced, true
+}
+
+// Rewrite applies the rules to e and its subexpressions until none of them
+// apply anymore.  Operands are rewritten before the expressions that contain
+// them and the first rule that applies to an expression is used.  Rules that
+// undo each other (e.g. a commutative rule) make Rewrite loop forever.
+// Vars in e are kept as they are.
+func Rewrite(e Expr, rules ...RewriteRule) Expr {
+	e = mapOperands(e, func(operand Expr) Expr {
+		return Rewrite(operand, rules...)
+	})
+	for _, r := range rules {
+		if replaced, ok := r.apply(e); ok {
+			return Rewrite(replaced, rules...)
+		}
+	}
+	return e
+}
+
+// RewriteMapper creates a Mapper that rewrites expressions with the rules.
+func RewriteMapper(rules ...RewriteRule) Mapper {
+	return func(e Expr) Expr {
+		return Rewrite(e, rules...)
+	}
+}
+
+// simplifyRules are the rules that Simplify applies.  The arithmetic rules
+// only apply when removing the identity cannot change the value or the type
+// of the result (see keepsType), so, for example, String("a") + 0 and
+// Int8(1) * 1 are left as they are.
+var simplifyRules = func() []RewriteRule {
+	x := Placeholder("x")
+	numeric := func(c Captures) bool { return keepsType(c[x]) }
+	return []RewriteRule{
+		{Pattern: Add{x, Int(0)}, Replacement: x, When: numeric},
+		{Pattern: Add{Int(0), x}, Replacement: x, When: numeric},
+		{Pattern: Sub{x, Int(0)}, Replacement: x, When: numeric},
+		{Pattern: Mul{x, Int(1)}, Replacement: x, When: numeric},
+		{Pattern: Mul{Int(1), x}, Replacement: x, When: numeric},
+		{Pattern: Div{x, Int(1)}, Replacement: x, When: numeric},
+		{Pattern: Pow{x, Int(1)}, Replacement: x, When: numeric},
+		{Pattern: Not{Not{x}}, Replacement: x},
+		{Pattern: All{x}, Replacement: x},
+		{Pattern: Any{x}, Replacement: x},
+	}
+}()
+
+// keepsType checks if e is known to evaluate to a Float64 or a Rational.
+// Arithmetic between one of those and an Int has the same type, so the Int
+// identities can be removed from it.  Other numbers, like fixed-width
+// integers and Decimals, are widened or rescaled by arithmetic with an Int.
+func keepsType(e Expr) bool {
+	switch e := e.(type) {
+	case Add, Sub, Mul, Div, Mod, Pow:
+		for _, operand := range Operands(e) {
+			if !keepsType(operand) {
+				return false
+			}
+		}
+		return true
+	case ValueExpr:
+		switch typeOfExpr(e) {
+		case Float64Type, RationalType:
+			return true
+		}
+	}
+	return false
+}
+
+// simplifyMapper applies simplifyRules to e until none of them apply.  Copy
+// maps operands before the expressions that contain them and the rules'
+// replacements are operands of e, so the operands don't have to be
+// rewritten again.
+func simplifyMapper(e Expr) Expr {
+	for {
+		replaced, ok := e, false
+		for _, r := range simplifyRules {
+			if replaced, ok = r.apply(e); ok {
+				break
+			}
+		}
+		if !ok {
+			return e
+		}
+		e = replaced
+	}
+}
+
+// SimplifyRules gets a copy of the rules that Simplify applies so that they
+// can be combined with other rules.
+func SimplifyRules() []RewriteRule {
+	return append([]RewriteRule(nil), simplifyRules...)
+}
@@ -0,0 +1,166 @@
+package expr_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	var a, b expr.Int
+	var p expr.Bool
+	x, y := expr.Placeholder("x"), expr.Placeholder("y")
+	person := expr.ValueOf(&struct{ Age, Height int }{})
+	tcs := []struct {
+		pattern, e expr.Expr
+		captures   expr.Captures
+	}{
+		{expr.Add{x, expr.Int(0)}, expr.Add{&a, expr.Int(0)}, expr.Captures{x: &a}},
+		{expr.Add{x, expr.Int(0)}, expr.Add{&a, expr.Int(1)}, nil},
+		{expr.Add{x, expr.Int(0)}, expr.Add{&a, expr.Float64(0)}, nil},
+		{expr.Add{x, expr.Int(0)}, expr.Sub{&a, expr.Int(0)}, nil},
+		{expr.Add{x, y}, expr.Add{expr.Mul{&a, &b}, &b}, expr.Captures{x: expr.Mul{&a, &b}, y: &b}},
+		{expr.Sub{x, x}, expr.Sub{expr.Mul{&a, &b}, expr.Mul{&a, &b}}, expr.Captures{x: expr.Mul{&a, &b}}},
+		{expr.Sub{x, x}, expr.Sub{&a, &b}, nil},
+		{expr.Add{&a, x}, expr.Add{&a, &b}, expr.Captures{x: &b}},
+		{expr.Add{&a, x}, expr.Add{&b, &b}, nil},
+		{expr.Not{expr.Not{x}}, expr.Not{expr.Not{&p}}, expr.Captures{x: &p}},
+		{expr.All{x, y}, expr.All{&p, expr.True}, expr.Captures{x: &p, y: expr.True}},
+		{expr.All{x, y}, expr.All{&p, expr.True, &p}, nil},
+		{expr.Attr{x, "Age"}, expr.Attr{person, "Age"}, expr.Captures{x: person}},
+		{expr.Attr{x, "Age"}, expr.Attr{person, "Height"}, nil},
+		{x, expr.Int(3), expr.Captures{x: expr.Int(3)}},
+	}
+	for _, tc := range tcs {
+		c, ok := expr.Match(tc.pattern, tc.e)
+		if ok != (tc.captures != nil) {
+			t.Errorf("%v matching %v: %v", tc.pattern, tc.e, ok)
+			continue
+		}
+		if len(c) != len(tc.captures) {
+			t.Errorf("%v matching %v: captured %v (expected %v)", tc.pattern, tc.e, c, tc.captures)
+		}
+		for p, expect := range tc.captures {
+			if !expr.SameExpr(c[p], expect) {
+				t.Errorf("%v matching %v: captured %v as %v (expected %v)", tc.pattern, tc.e, p, c[p], expect)
+			}
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	t.Parallel()
+	var a expr.Int
+	x, y := expr.Placeholder("x"), expr.Placeholder("y")
+	e, err := expr.Substitute(expr.Mul{x, expr.Add{x, y}}, expr.Captures{x: &a, y: expr.Int(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (expr.Mul{&a, expr.Add{&a, expr.Int(2)}}); !expr.SameExpr(e, expect) {
+		t.Errorf("expected %v but got %v", expect, e)
+	}
+	if e, err := expr.Substitute(expr.Add{x, y}, expr.Captures{x: &a}); err == nil {
+		t.Errorf("expected substituting an uncaptured placeholder to fail but got %v", e)
+	}
+	if e, err := expr.Substitute(expr.Not{x}, expr.Captures{x: &a}); err == nil {
+		t.Errorf("expected substituting a non-BoolExpr into Not to fail but got %v", e)
+	}
+	if _, err := x.EvalValue(); err == nil {
+		t.Errorf("expected evaluating %v to fail", x)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+	var a, b expr.Int
+	x, y, z := expr.Placeholder("x"), expr.Placeholder("y"), expr.Placeholder("z")
+	rules := []expr.RewriteRule{
+		// Distribute multiplication over addition:
+		{Pattern: expr.Mul{x, expr.Add{y, z}}, Replacement: expr.Add{expr.Mul{x, y}, expr.Mul{x, z}}},
+		// Move constants to the left of products:
+		{
+			Pattern:     expr.Mul{x, y},
+			Replacement: expr.Mul{y, x},
+			When: func(c expr.Captures) bool {
+				return expr.IsConst(c[y]) && !expr.IsConst(c[x])
+			},
+		},
+		{Pattern: expr.Mul{expr.Int(1), x}, Replacement: x},
+	}
+	tcs := []struct {
+		e, expect expr.Expr
+	}{
+		{expr.Mul{&a, expr.Int(1)}, &a},
+		{expr.Mul{&a, expr.Int(2)}, expr.Mul{expr.Int(2), &a}},
+		{
+			expr.Mul{&a, expr.Add{expr.Mul{&b, expr.Int(1)}, expr.Int(3)}},
+			expr.Add{expr.Mul{&a, &b}, expr.Mul{expr.Int(3), &a}},
+		},
+		{
+			expr.Sub{expr.Mul{expr.Add{&a, expr.Int(1)}, expr.Int(1)}, &b},
+			expr.Sub{expr.Add{&a, expr.Int(1)}, &b},
+		},
+		{expr.Div{&a, &b}, expr.Div{&a, &b}},
+	}
+	for _, tc := range tcs {
+		if actual := expr.Rewrite(tc.e, rules...); !expr.SameExpr(actual, tc.expect) {
+			t.Errorf("%v: expected %v but got %v", tc.e, tc.expect, actual)
+		}
+	}
+}
+
+func TestSimplifyRules(t *testing.T) {
+	t.Parallel()
+	var r expr.Float64
+	var i expr.Int
+	var s expr.String
+	var p expr.Bool
+	third := (*expr.Rational)(big.NewRat(1, 3))
+	a := expr.Attr{expr.ValueOf(&struct{ Price int }{}), "Price"}
+	tcs := []struct {
+		e, expect expr.Expr
+	}{
+		{expr.Add{expr.Mul{&r, expr.Int(1)}, expr.Int(0)}, &r},
+		{expr.Div{expr.Pow{expr.Sub{&r, expr.Int(0)}, expr.Int(1)}, expr.Int(1)}, &r},
+		{expr.Add{expr.Int(0), expr.Mul{expr.Int(1), expr.Add{&r, &r}}}, expr.Add{&r, &r}},
+		{expr.Mul{&r, expr.Int(2)}, expr.Mul{&r, expr.Int(2)}},
+		{expr.Add{expr.Float64(1.5), expr.Int(0)}, expr.Float64(1.5)},
+		{expr.Add{expr.String("a"), expr.Int(0)}, expr.Add{expr.String("a"), expr.Int(0)}},
+		{expr.Mul{&s, expr.Int(1)}, expr.Mul{&s, expr.Int(1)}},
+		{expr.Add{expr.Add{&s, &r}, expr.Int(0)}, expr.Add{expr.Add{&s, &r}, expr.Int(0)}},
+		{expr.Add{a, expr.Int(0)}, expr.Add{a, expr.Int(0)}},
+		// The identities would change the types of these results:
+		{expr.Mul{&i, expr.Int(1)}, expr.Mul{&i, expr.Int(1)}},
+		{expr.Mul{expr.Int8(3), expr.Int(1)}, expr.Mul{expr.Int8(3), expr.Int(1)}},
+		{expr.Add{expr.Float32(1), expr.Int(0)}, expr.Add{expr.Float32(1), expr.Int(0)}},
+		{expr.Mul{mustParseDecimal(t, "1.5"), expr.Int(1)}, expr.Mul{mustParseDecimal(t, "1.5"), expr.Int(1)}},
+		{expr.Div{expr.Add{third, &r}, expr.Int(1)}, expr.Add{third, &r}},
+		{expr.Div{expr.Add{third, &i}, expr.Int(1)}, expr.Div{expr.Add{third, &i}, expr.Int(1)}},
+		{expr.Not{expr.Not{expr.All{expr.Any{expr.Not{expr.Not{expr.True}}}}}}, expr.True},
+	}
+	for _, tc := range tcs {
+		if actual := expr.Rewrite(tc.e, expr.SimplifyRules()...); !expr.SameExpr(actual, tc.expect) {
+			t.Errorf("%v: expected %v but got %v", tc.e, tc.expect, actual)
+		}
+	}
+	// Simplify copies Vars into their values, so only constants are
+	// used with it:
+	tcs = []struct {
+		e, expect expr.Expr
+	}{
+		{expr.Add{expr.Mul{expr.Float64(3), expr.Int(1)}, expr.Int(0)}, expr.Float64(3)},
+		{expr.Add{expr.Mul{expr.Int(3), expr.Int(1)}, expr.Int(0)}, expr.Add{expr.Mul{expr.Int(3), expr.Int(1)}, expr.Int(0)}},
+		{expr.Add{expr.String("a"), expr.Int(0)}, expr.Add{expr.String("a"), expr.Int(0)}},
+		{expr.Not{expr.Not{expr.All{expr.Any{expr.Not{expr.Not{expr.True}}}}}}, expr.True},
+	}
+	for _, tc := range tcs {
+		if actual := expr.Simplify(tc.e); !expr.SameExpr(actual, tc.expect) {
+			t.Errorf("%v: expected %v but got %v", tc.e, tc.expect, actual)
+		}
+	}
+	if actual := expr.Rewrite(expr.All{expr.Not{expr.Not{&p}}}, expr.SimplifyRules()...); actual != expr.Expr(&p) {
+		t.Errorf("expected %v but got %v", &p, actual)
+	}
+}
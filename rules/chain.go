@@ -0,0 +1,367 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxIterations is the number of rules that an Engine fires before it
+// gives up if its MaxIterations is not set.
+const DefaultMaxIterations = 10000
+
+// WorkingMemory holds the facts that an Engine's rules are matched against.
+type WorkingMemory struct {
+	facts  []*factEntry
+	nextID int
+}
+
+// factEntry is a fact in working memory.  Each fact gets an ID when it is
+// asserted so that the rules fired on it can be tracked even after other
+// facts are retracted.
+type factEntry struct {
+	id   int
+	fact interface{}
+}
+
+// NewWorkingMemory creates a WorkingMemory with the given facts.
+func NewWorkingMemory(facts ...interface{}) *WorkingMemory {
+	wm := &WorkingMemory{}
+	for _, fact := range facts {
+		wm.Assert(fact)
+	}
+	return wm
+}
+
+// Assert adds a fact to the working memory.  It returns false if the fact is
+// already in the working memory.  Facts are usually pointers so that rules
+// can modify them.
+func (wm *WorkingMemory) Assert(fact interface{}) bool {
+	if wm.index(fact) != -1 {
+		return false
+	}
+	wm.facts = append(wm.facts, &factEntry{id: wm.nextID, fact: fact})
+	wm.nextID++
+	return true
+}
+
+// Retract removes a fact from the working memory.  It returns false if the
+// fact was not in the working memory.
+func (wm *WorkingMemory) Retract(fact interface{}) bool {
+	i := wm.index(fact)
+	if i == -1 {
+		return false
+	}
+	wm.facts = append(wm.facts[:i], wm.facts[i+1:]...)
+	return true
+}
+
+// Contains checks if the fact is in the working memory.
+func (wm *WorkingMemory) Contains(fact interface{}) bool {
+	return wm.index(fact) != -1
+}
+
+// Facts gets the facts in the order that they were asserted.
+func (wm *WorkingMemory) Facts() []interface{} {
+	facts := make([]interface{}, len(wm.facts))
+	for i, e := range wm.facts {
+		facts[i] = e.fact
+	}
+	return facts
+}
+
+// Len gets the number of facts in the working memory.
+func (wm *WorkingMemory) Len() int {
+	return len(wm.facts)
+}
+
+func (wm *WorkingMemory) index(fact interface{}) int {
+	for i, e := range wm.facts {
+		if sameFact(e.fact, fact) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fingerprint represents the state of every fact in the working memory.
+func (wm *WorkingMemory) fingerprint() string {
+	fps := make([]string, len(wm.facts))
+	for i, e := range wm.facts {
+		fps[i] = fmt.Sprintf("%d:%s", e.id, factFingerprint(e.fact))
+	}
+	return fmt.Sprint(fps)
+}
+
+// sameFact checks if a and b are the same fact.  Pointers are the same fact
+// if they point to the same value.
+func sameFact(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if a == nil || reflect.TypeOf(a).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// factFingerprint represents the state of a fact so that changes to the fact
+// can be detected.  The values behind pointers, maps, slices and interfaces
+// in the fact are part of its state.
+func factFingerprint(fact interface{}) string {
+	var sb strings.Builder
+	writeFingerprint(&sb, reflect.ValueOf(fact), make(map[uintptr]struct{}))
+	return sb.String()
+}
+
+// writeFingerprint writes the state of v into sb.  path holds the pointers
+// being written so that cyclic values are only written once.
+func writeFingerprint(sb *strings.Builder, v reflect.Value, path map[uintptr]struct{}) {
+	if !v.IsValid() {
+		sb.WriteString("nil")
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		p := v.Pointer()
+		if _, ok := path[p]; ok {
+			fmt.Fprintf(sb, "@%x", p)
+			return
+		}
+		path[p] = struct{}{}
+		defer delete(path, p)
+		sb.WriteString("&")
+		writeFingerprint(sb, v.Elem(), path)
+	case reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		fmt.Fprintf(sb, "%v(", v.Elem().Type())
+		writeFingerprint(sb, v.Elem(), path)
+		sb.WriteString(")")
+	case reflect.Struct:
+		fmt.Fprintf(sb, "%v{", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			writeFingerprint(sb, v.Field(i), path)
+			sb.WriteString(";")
+		}
+		sb.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		fmt.Fprintf(sb, "[%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			writeFingerprint(sb, v.Index(i), path)
+			sb.WriteString(",")
+		}
+		sb.WriteString("]")
+	case reflect.Map:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			writeFingerprint(&entry, iter.Key(), path)
+			entry.WriteString(":")
+			writeFingerprint(&entry, iter.Value(), path)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		sb.WriteString("map[" + strings.Join(entries, ",") + "]")
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		fmt.Fprintf(sb, "@%x", v.Pointer())
+	case reflect.String:
+		sb.WriteString(strconv.Quote(v.String()))
+	default:
+		fmt.Fprint(sb, v)
+	}
+}
+
+type memoryKey struct{}
+
+// WithMemory returns a copy of ctx that rule actions can get the working
+// memory from to assert or retract facts.
+func WithMemory(ctx context.Context, wm *WorkingMemory) context.Context {
+	return context.WithValue(ctx, memoryKey{}, wm)
+}
+
+// MemoryFromContext gets the working memory associated with the context or
+// nil if there isn't one.
+func MemoryFromContext(ctx context.Context) *WorkingMemory {
+	wm, _ := ctx.Value(memoryKey{}).(*WorkingMemory)
+	return wm
+}
+
+// Engine performs forward chaining:  It repeatedly matches its rules against
+// the facts in its working memory and fires one of the matching rules until
+// no rule matches.  Rule actions can change facts or assert and retract facts
+// through the working memory from MemoryFromContext, which causes other rules
+// to match.
+//
+// A rule is not fired on the same fact twice unless the fact has changed
+// since the rule was last fired on it, even across calls to Run.
+type Engine struct {
+	// Rules are matched against the working memory.  The RuleSet's
+	// Strategy orders the agenda:  With PriorityMatch and HighestPriority,
+	// the activation with the highest priority is fired first.  Otherwise,
+	// the activations are fired in the order that their rules were added.
+	// Activations of the same rule are fired in the order that their facts
+	// were asserted.
+	Rules *RuleSet
+
+	// Memory holds the facts.
+	Memory *WorkingMemory
+
+	// MaxIterations limits the number of rules fired by Run.  If it is 0,
+	// DefaultMaxIterations is used.
+	MaxIterations int
+
+	// fired holds the rules already fired on facts so that running the
+	// Engine again only fires rules on new or changed facts.
+	fired map[refraction]struct{}
+}
+
+// NewEngine creates an Engine of the rules with a working memory of the
+// facts.
+func NewEngine(rs *RuleSet, facts ...interface{}) *Engine {
+	return &Engine{Rules: rs, Memory: NewWorkingMemory(facts...)}
+}
+
+// Firing records a rule that was fired by an Engine.
+type Firing struct {
+	// Iteration is the number of rules fired before this one.
+	Iteration int
+	Rule      *Rule
+	Fact      interface{}
+}
+
+// activation is a rule whose condition is true for a fact.
+type activation struct {
+	rule int
+	fact *factEntry
+}
+
+// refraction identifies a rule fired on a fact in a specific state.
+type refraction struct {
+	rule        *Rule
+	fact        int
+	fingerprint string
+}
+
+// Run fires rules until none match.  It returns the rules that were fired in
+// order.  A *CycleError is returned if the rules return the working memory to
+// a state that it was in before and more rules would fire in that state.  A
+// *MaxIterationsError is returned if more than MaxIterations rules would be
+// fired.
+func (e *Engine) Run(ctx context.Context) ([]Firing, error) {
+	max := e.MaxIterations
+	if max == 0 {
+		max = DefaultMaxIterations
+	}
+	ctx = WithMemory(ctx, e.Memory)
+	if e.fired == nil {
+		e.fired = make(map[refraction]struct{})
+	}
+	state := e.Memory.fingerprint()
+	states := map[string]struct{}{state: {}}
+	var firings []Firing
+	revisited := false
+	for {
+		if err := ctx.Err(); err != nil {
+			return firings, err
+		}
+		agenda, err := e.agenda(ctx)
+		if err != nil {
+			return firings, err
+		}
+		if len(agenda) == 0 {
+			return firings, nil
+		}
+		if revisited {
+			return firings, &CycleError{Firings: firings}
+		}
+		if len(firings) == max {
+			return firings, &MaxIterationsError{Max: max}
+		}
+		act := agenda[0]
+		r := e.Rules.rules[act.rule]
+		e.fired[refraction{r, act.fact.id, factFingerprint(act.fact.fact)}] = struct{}{}
+		if err = r.Fire(ctx, act.fact.fact); err != nil {
+			return firings, err
+		}
+		firings = append(firings, Firing{Iteration: len(firings), Rule: r, Fact: act.fact.fact})
+		next := e.Memory.fingerprint()
+		if next == state {
+			continue
+		}
+		_, revisited = states[next]
+		states[next] = struct{}{}
+		state = next
+	}
+}
+
+// agenda matches the rules against the facts and gets the activations that
+// have not already fired ordered by the RuleSet's Strategy.
+func (e *Engine) agenda(ctx context.Context) ([]activation, error) {
+	var agenda []activation
+	for i, r := range e.Rules.rules {
+		for _, f := range e.Memory.facts {
+			if _, ok := e.fired[refraction{r, f.id, factFingerprint(f.fact)}]; ok {
+				continue
+			}
+			ok, err := r.Match(ctx, f.fact)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				agenda = append(agenda, activation{rule: i, fact: f})
+			}
+		}
+	}
+	switch e.Rules.Strategy {
+	case PriorityMatch, HighestPriority:
+		sort.SliceStable(agenda, func(i, j int) bool {
+			return e.Rules.rules[agenda[i].rule].Priority > e.Rules.rules[agenda[j].rule].Priority
+		})
+	}
+	return agenda, nil
+}
+
+// CycleError is returned by Engine.Run when the rules return the working
+// memory to a state that it was already in, so they would keep firing
+// forever.
+type CycleError struct {
+	// Firings are the rules fired up to and including the one that
+	// completed the cycle.
+	Firings []Firing
+}
+
+func (e *CycleError) Error() string {
+	last := e.Firings[len(e.Firings)-1]
+	return fmt.Sprintf(
+		"rule %q returned the working memory to a previous state after %d iterations",
+		last.Rule.Name, len(e.Firings))
+}
+
+// MaxIterationsError is returned by Engine.Run when more than the maximum
+// number of rules would be fired.
+type MaxIterationsError struct {
+	Max int
+}
+
+func (e *MaxIterationsError) Error() string {
+	return fmt.Sprintf("exceeded the maximum of %d iterations", e.Max)
+}
@@ -0,0 +1,188 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+	"github.com/skillian/expr/rules"
+)
+
+type shipment struct {
+	Weight   float64
+	Class    string
+	Priority bool
+}
+
+type notice struct {
+	Message string
+	Sent    bool
+}
+
+func TestEngine(t *testing.T) {
+	t.Parallel()
+	sent := 0
+	rs := rules.NewRuleSet(
+		rules.PriorityMatch,
+		&rules.Rule{
+			Name:     "heavy",
+			FactType: reflect.TypeOf(&shipment{}),
+			When:     expr.All{expr.Gt{rules.Field("Weight"), expr.Float64(50)}, expr.Eq{rules.Field("Class"), expr.String("")}},
+			Assign:   []rules.Assignment{rules.Set(rules.Field("Class"), expr.String("freight"))},
+		},
+		&rules.Rule{
+			Name:     "light",
+			FactType: reflect.TypeOf(&shipment{}),
+			When:     expr.All{expr.Le{rules.Field("Weight"), expr.Float64(50)}, expr.Eq{rules.Field("Class"), expr.String("")}},
+			Assign:   []rules.Assignment{rules.Set(rules.Field("Class"), expr.String("parcel"))},
+		},
+		&rules.Rule{
+			Name:     "freight",
+			Priority: 1,
+			FactType: reflect.TypeOf(&shipment{}),
+			When:     expr.All{expr.Eq{rules.Field("Class"), expr.String("freight")}, expr.Eq{rules.Field("Priority"), expr.False}},
+			Then: func(ctx context.Context, fact interface{}) error {
+				rules.MemoryFromContext(ctx).Assert(&notice{Message: "book a truck"})
+				return nil
+			},
+		},
+		&rules.Rule{
+			Name:     "send",
+			FactType: reflect.TypeOf(&notice{}),
+			When:     expr.Eq{rules.Field("Sent"), expr.False},
+			Assign:   []rules.Assignment{rules.Set(rules.Field("Sent"), expr.True)},
+			Then: func(ctx context.Context, fact interface{}) error {
+				sent++
+				return nil
+			},
+		},
+	)
+	light, heavy := &shipment{Weight: 10}, &shipment{Weight: 80}
+	e := rules.NewEngine(rs, light, heavy)
+	firings, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(firings))
+	for i, f := range firings {
+		names[i] = f.Rule.Name
+	}
+	expect := []string{"heavy", "freight", "light", "send"}
+	if !reflect.DeepEqual(names, expect) {
+		t.Errorf("fired %v (expected %v)", names, expect)
+	}
+	if light.Class != "parcel" || heavy.Class != "freight" || sent != 1 {
+		t.Errorf("unexpected results: %+v, %+v, %d sent", light, heavy, sent)
+	}
+	if e.Memory.Len() != 3 {
+		t.Errorf("expected 3 facts but got %v", e.Memory.Facts())
+	}
+	if firings, err = e.Run(context.Background()); err != nil || len(firings) != 0 {
+		t.Errorf("expected nothing to fire again but fired %v (err: %v)", firings, err)
+	}
+}
+
+func TestEngineRefraction(t *testing.T) {
+	t.Parallel()
+	count := 0
+	rs := rules.NewRuleSet(rules.FirstMatch, &rules.Rule{
+		Name: "count",
+		When: expr.True,
+		Then: func(ctx context.Context, fact interface{}) error {
+			count++
+			return nil
+		},
+	})
+	if _, err := rules.NewEngine(rs, &notice{}, &notice{}).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected the rule to fire once per fact but it fired %d times", count)
+	}
+}
+
+func TestEngineNestedChanges(t *testing.T) {
+	t.Parallel()
+	type tagged struct {
+		Owner *customer
+		Tags  map[string]*notice
+	}
+	fired := 0
+	rs := rules.NewRuleSet(rules.FirstMatch, &rules.Rule{
+		Name: "count",
+		When: expr.True,
+		Then: func(ctx context.Context, fact interface{}) error {
+			fired++
+			return nil
+		},
+	})
+	fact := &tagged{Owner: &customer{}, Tags: map[string]*notice{"a": {}}}
+	e := rules.NewEngine(rs, fact)
+	for i, change := range []func(){
+		func() {},
+		func() { fact.Owner.VIP = true },
+		func() { fact.Tags["a"].Sent = true },
+	} {
+		change()
+		if _, err := e.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if fired != i+1 {
+			t.Errorf("expected %d firings after change %d but got %d", i+1, i, fired)
+		}
+	}
+	if _, err := e.Run(context.Background()); err != nil || fired != 3 {
+		t.Errorf("expected the unchanged fact not to fire again (fired: %d, err: %v)", fired, err)
+	}
+}
+
+func TestEngineLimits(t *testing.T) {
+	t.Parallel()
+	flip := rules.NewRuleSet(
+		rules.AllMatch,
+		&rules.Rule{
+			Name:   "on",
+			When:   expr.Eq{rules.Field("Sent"), expr.False},
+			Assign: []rules.Assignment{rules.Set(rules.Field("Sent"), expr.True)},
+		},
+		&rules.Rule{
+			Name:   "off",
+			When:   expr.Eq{rules.Field("Sent"), expr.True},
+			Assign: []rules.Assignment{rules.Set(rules.Field("Sent"), expr.False)},
+		},
+	)
+	// The rules are not fired again in the states they were fired in:
+	if firings, err := rules.NewEngine(flip, &notice{}).Run(context.Background()); err != nil || len(firings) != 2 {
+		t.Errorf("expected 2 firings but got %d (err: %v)", len(firings), err)
+	}
+	flip.Add(&rules.Rule{
+		Name:   "resend",
+		When:   expr.All{expr.Eq{rules.Field("Sent"), expr.False}, expr.Ne{rules.Field("Message"), expr.String("")}},
+		Assign: []rules.Assignment{rules.Set(rules.Field("Message"), expr.String(""))},
+	})
+	_, err := rules.NewEngine(flip, &notice{Message: "m"}).Run(context.Background())
+	var ce *rules.CycleError
+	if !errors.As(err, &ce) || len(ce.Firings) != 2 {
+		t.Errorf("expected *CycleError after 2 firings but got %v", err)
+	}
+	grow := rules.NewRuleSet(rules.AllMatch, &rules.Rule{
+		Name:   "grow",
+		When:   expr.Gt{rules.Field("Weight"), expr.Float64(0)},
+		Assign: []rules.Assignment{rules.Set(rules.Field("Weight"), expr.Add{rules.Field("Weight"), expr.Float64(1)})},
+	})
+	s := &shipment{Weight: 1}
+	e := rules.NewEngine(grow, s)
+	e.MaxIterations = 10
+	firings, err := e.Run(context.Background())
+	var me *rules.MaxIterationsError
+	if !errors.As(err, &me) || len(firings) != 10 || s.Weight != 11 {
+		t.Errorf("expected *MaxIterationsError after 10 firings but got %v after %d (weight: %v)", err, len(firings), s.Weight)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = rules.NewEngine(grow, &shipment{Weight: 1}).Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package rules
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/skillian/errors"
+	"github.com/skillian/expr"
+)
+
+// Network matches rules against facts incrementally.  The rules' conditions
+// are broken down into a network of condition nodes where structurally equal
+// subexpressions (according to expr.SameExpr) share a single node, so each
+// distinct test is only evaluated once per fact no matter how many rules use
+// it.  The results of every node are remembered for each fact so that when a
+// fact's fields change, only the nodes that depend on those fields are
+// evaluated again.
+//
+// Facts are identified by their Go values, so they must be comparable.
+// They are usually pointers.
+type Network struct {
+	rules []*Rule
+	roots []*node
+	nodes []*node
+	index map[uint64][]*node
+	facts map[interface{}]*factState
+}
+
+// nodeKind is the kind of a condition node.
+type nodeKind int
+
+const (
+	// testNode evaluates its expression.
+	testNode nodeKind = iota
+	// allNode is true when all of its children are true.
+	allNode
+	// anyNode is true when any of its children are true.
+	anyNode
+	// notNode is true when its child is false.
+	notNode
+)
+
+// node is a condition in a Network.
+type node struct {
+	id       int
+	kind     nodeKind
+	e        expr.BoolExpr
+	children []*node
+	parents  []*node
+
+	// fields are the names of the fact's top-level fields that a testNode
+	// depends on.  If anyField is true, the node depends on the whole fact
+	// or on Vars outside of the fact.
+	fields   map[string]struct{}
+	anyField bool
+}
+
+// result is the result of a node for a fact.
+type result struct {
+	ok  bool
+	err error
+}
+
+// same checks if r and o are the same successful result.
+func (r result) same(o result) bool {
+	return r.ok == o.ok && r.err == nil && o.err == nil
+}
+
+// factState holds the results of every node for a fact.
+type factState struct {
+	fact    interface{}
+	results []result
+}
+
+// NewNetwork creates a Network of the rules.
+func NewNetwork(rules ...*Rule) *Network {
+	n := &Network{
+		index: make(map[uint64][]*node),
+		facts: make(map[interface{}]*factState),
+	}
+	for _, r := range rules {
+		n.rules = append(n.rules, r)
+		n.roots = append(n.roots, n.node(r.When))
+	}
+	return n
+}
+
+// node gets the node for e, creating it and its children if an equivalent
+// node doesn't already exist.  Children are always created before their
+// parents so the nodes are in the order that they must be evaluated.
+func (n *Network) node(e expr.BoolExpr) *node {
+	h := expr.HashExpr(e)
+	for _, nd := range n.index[h] {
+		if expr.SameExpr(nd.e, e) {
+			return nd
+		}
+	}
+	nd := &node{e: e}
+	switch e := e.(type) {
+	case expr.All:
+		nd.kind = allNode
+		for _, operand := range e {
+			nd.children = append(nd.children, n.node(operand))
+		}
+	case expr.Any:
+		nd.kind = anyNode
+		for _, operand := range e {
+			nd.children = append(nd.children, n.node(operand))
+		}
+	case expr.Not:
+		nd.kind = notNode
+		nd.children = []*node{n.node(e.Operand().(expr.BoolExpr))}
+	default:
+		nd.kind = testNode
+		nd.fields = make(map[string]struct{})
+		nd.collectFields(e)
+	}
+	for _, child := range nd.children {
+		child.parents = append(child.parents, nd)
+	}
+	nd.id = len(n.nodes)
+	n.nodes = append(n.nodes, nd)
+	n.index[h] = append(n.index[h], nd)
+	return nd
+}
+
+// collectFields finds the fields of the fact that e depends on.  Nested
+// fields, like Field("Customer", "Name"), depend on their top-level field
+// (e.g. "Customer").  Vars can change without the fact changing, so the
+// nodes that use them are evaluated by every Update.
+func (nd *node) collectFields(e expr.Expr) {
+	switch e := e.(type) {
+	case Fact, expr.Var:
+		nd.anyField = true
+		return
+	case expr.Attr:
+		if _, ok := e.ValueExpr.(Fact); ok {
+			nd.fields[string(e.Name)] = struct{}{}
+			return
+		}
+	}
+	for _, operand := range expr.Operands(e) {
+		nd.collectFields(operand)
+	}
+}
+
+// Len gets the number of distinct condition nodes in the network.
+func (n *Network) Len() int {
+	return len(n.nodes)
+}
+
+// Add a fact to the network and evaluate all of the conditions for it.  If
+// the fact was already added, all of its conditions are evaluated again.
+func (n *Network) Add(ctx context.Context, fact interface{}) error {
+	if t := reflect.TypeOf(fact); t != nil && !t.Comparable() {
+		return errors.Errorf("fact %v of type %v is not comparable", fact, t)
+	}
+	fs := &factState{fact: fact, results: make([]result, len(n.nodes))}
+	n.facts[fact] = fs
+	dirty := make([]bool, len(n.nodes))
+	for i := range dirty {
+		dirty[i] = true
+	}
+	return n.eval(ctx, fs, dirty)
+}
+
+// Remove a fact from the network.
+func (n *Network) Remove(fact interface{}) {
+	delete(n.facts, fact)
+}
+
+// Update re-evaluates the conditions of a fact that depend on the given
+// fields.  The fields are the names of the fact's top-level fields, so a
+// change to a nested field, like the Name of the fact's Customer, is an
+// update of "Customer".  Conditions that use Vars are always evaluated
+// again.  If no fields are given, every condition is evaluated again.  The
+// fact is added to the network if it isn't already in it.
+func (n *Network) Update(ctx context.Context, fact interface{}, fields ...string) error {
+	fs, ok := n.facts[fact]
+	if !ok || len(fields) == 0 {
+		return n.Add(ctx, fact)
+	}
+	dirty := make([]bool, len(n.nodes))
+	for _, nd := range n.nodes {
+		if nd.kind != testNode {
+			continue
+		}
+		if nd.anyField {
+			dirty[nd.id] = true
+			continue
+		}
+		for _, f := range fields {
+			if _, ok := nd.fields[f]; ok {
+				dirty[nd.id] = true
+				break
+			}
+		}
+	}
+	return n.eval(ctx, fs, dirty)
+}
+
+// Set sets the fact's field to v and updates the conditions that depend on
+// it.
+func (n *Network) Set(ctx context.Context, fact interface{}, field string, v expr.Value) error {
+	if err := expr.SetAttrValue(expr.ValueOf(fact), expr.String(field), v); err != nil {
+		return err
+	}
+	return n.Update(ctx, fact, field)
+}
+
+// eval evaluates the dirty nodes for a fact.  Parents of nodes whose results
+// change are evaluated too.
+func (n *Network) eval(ctx context.Context, fs *factState, dirty []bool) error {
+	ctx = WithFact(ctx, fs.fact)
+	for _, nd := range n.nodes {
+		if !dirty[nd.id] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r := nd.eval(ctx, fs)
+		if r.same(fs.results[nd.id]) {
+			continue
+		}
+		fs.results[nd.id] = r
+		for _, p := range nd.parents {
+			dirty[p.id] = true
+		}
+	}
+	return nil
+}
+
+// eval evaluates the node for the fact.  The results of the node's children
+// must already be evaluated.
+func (nd *node) eval(ctx context.Context, fs *factState) result {
+	switch nd.kind {
+	case allNode:
+		for _, c := range nd.children {
+			if r := fs.results[c.id]; r.err != nil || !r.ok {
+				return r
+			}
+		}
+		return result{ok: true}
+	case anyNode:
+		for _, c := range nd.children {
+			if r := fs.results[c.id]; r.err != nil || r.ok {
+				return r
+			}
+		}
+		return result{}
+	case notNode:
+		r := fs.results[nd.children[0].id]
+		r.ok = !r.ok && r.err == nil
+		return r
+	}
+	ok, err := expr.EvalBoolContext(ctx, nd.e)
+	return result{ok, err}
+}
+
+// Matches gets the rules whose conditions are true for the fact in the order
+// that the rules were given to NewNetwork.
+func (n *Network) Matches(fact interface{}) ([]*Rule, error) {
+	fs, ok := n.facts[fact]
+	if !ok {
+		return nil, errors.Errorf("fact %v is not in the network", fact)
+	}
+	var matches []*Rule
+	for i, r := range n.rules {
+		if r.FactType != nil && reflect.TypeOf(fact) != r.FactType {
+			continue
+		}
+		res := fs.results[n.roots[i].id]
+		if res.err != nil {
+			return nil, &RuleError{Rule: r.Name, Err: res.err}
+		}
+		if res.ok {
+			matches = append(matches, r)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,244 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+	"github.com/skillian/expr/rules"
+)
+
+type account struct {
+	Region  string
+	Balance float64
+	Active  bool
+	Owner   *customer
+}
+
+var regions = []string{"north", "south", "east", "west"}
+
+// accountRules creates count rules that share region, balance and activity
+// tests.
+func accountRules(count int) []*rules.Rule {
+	rs := make([]*rules.Rule, count)
+	for i := range rs {
+		rs[i] = &rules.Rule{
+			Name: fmt.Sprintf("rule %d", i),
+			When: expr.All{
+				expr.Eq{rules.Field("Active"), expr.True},
+				expr.Eq{rules.Field("Region"), expr.String(regions[i%len(regions)])},
+				expr.Any{
+					expr.Gt{rules.Field("Balance"), expr.Float64(float64(i%25) * 100)},
+					expr.Lt{rules.Field("Balance"), expr.Float64(-float64(i % 3))},
+				},
+			},
+		}
+	}
+	return rs
+}
+
+func naiveMatches(t testing.TB, rs []*rules.Rule, fact interface{}) []*rules.Rule {
+	var matches []*rules.Rule
+	for _, r := range rs {
+		ok, err := r.Match(context.Background(), fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	rs := accountRules(200)
+	n := rules.NewNetwork(rs...)
+	// Without sharing, each rule would need 6 nodes:
+	if n.Len() >= 200*6 {
+		t.Errorf("expected shared nodes but the network has %d", n.Len())
+	}
+	accts := []*account{
+		{Region: "north", Balance: 1000, Active: true},
+		{Region: "south", Balance: -5, Active: true},
+		{Region: "east", Balance: 50, Active: false},
+	}
+	check := func(a *account) {
+		t.Helper()
+		got, err := n.Matches(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := naiveMatches(t, rs, a); !reflect.DeepEqual(got, expect) {
+			t.Errorf("%+v: network matched %d rules but %d were expected", a, len(got), len(expect))
+		}
+	}
+	for _, a := range accts {
+		if err := n.Add(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		check(a)
+	}
+	updates := []struct {
+		field string
+		value expr.Value
+	}{
+		{"Active", expr.True},
+		{"Balance", expr.Float64(2500)},
+		{"Region", expr.String("west")},
+		{"Balance", expr.Float64(-1.5)},
+		{"Active", expr.False},
+	}
+	for _, u := range updates {
+		for _, a := range accts {
+			if err := n.Set(ctx, a, u.field, u.value); err != nil {
+				t.Fatal(err)
+			}
+			check(a)
+		}
+	}
+	// Changing a field without telling the network about it leaves stale
+	// results until the fact is updated:
+	accts[0].Active = true
+	if err := n.Update(ctx, accts[0], "Region"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := n.Matches(accts[0]); len(got) != 0 {
+		t.Errorf("expected stale results but matched %d rules", len(got))
+	}
+	if err := n.Update(ctx, accts[0]); err != nil {
+		t.Fatal(err)
+	}
+	check(accts[0])
+	n.Remove(accts[0])
+	if _, err := n.Matches(accts[0]); err == nil {
+		t.Errorf("expected an error for a removed fact")
+	}
+}
+
+func TestNetworkErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := rules.Field("Owner", "VIP")
+	n := rules.NewNetwork(
+		&rules.Rule{Name: "guarded", When: expr.All{expr.Ne{rules.Field("Owner"), expr.ValueOf((*customer)(nil))}, expr.Eq{owner, expr.True}}},
+		&rules.Rule{Name: "unguarded", When: expr.Eq{owner, expr.True}},
+	)
+	a := &account{}
+	if err := n.Add(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	_, err := n.Matches(a)
+	var re *rules.RuleError
+	if !errors.As(err, &re) || re.Rule != "unguarded" {
+		t.Errorf("expected *RuleError from %q but got %v", "unguarded", err)
+	}
+	if err := n.Set(ctx, a, "Owner", expr.ValueOf(&customer{VIP: true})); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := n.Matches(a); err != nil || len(got) != 2 {
+		t.Errorf("expected both rules to match but got %v (err: %v)", got, err)
+	}
+	if err := n.Add(ctx, account{}); err != nil {
+		t.Errorf("expected a comparable struct to be accepted: %v", err)
+	}
+	if err := n.Add(ctx, []int{1}); err == nil {
+		t.Errorf("expected a slice fact to be rejected")
+	}
+}
+
+func TestNetworkDependencies(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	limit := expr.Float64(100)
+	n := rules.NewNetwork(
+		&rules.Rule{Name: "over limit", When: expr.Gt{rules.Field("Balance"), &limit}},
+		&rules.Rule{Name: "vip", When: expr.Eq{rules.Field("Owner", "VIP"), expr.True}},
+	)
+	a := &account{Balance: 50, Owner: &customer{}}
+	if err := n.Add(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	limit = 10
+	a.Owner.VIP = true
+	if err := n.Update(ctx, a, "Region"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := n.Matches(a); err != nil || len(got) != 1 || got[0].Name != "over limit" {
+		t.Errorf("expected the Var's rule to match but got %v (err: %v)", got, err)
+	}
+	if err := n.Update(ctx, a, "Owner"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := n.Matches(a); err != nil || len(got) != 2 {
+		t.Errorf("expected both rules to match but got %v (err: %v)", got, err)
+	}
+}
+
+func TestNetworkDynamicConstants(t *testing.T) {
+	t.Parallel()
+	alice, bob := &customer{Name: "x"}, &customer{Name: "x"}
+	owns := func(c *customer) *rules.Rule {
+		return &rules.Rule{Name: c.Name, When: expr.Eq{rules.Field("Owner"), expr.ValueOf(c)}}
+	}
+	one := rules.NewNetwork(owns(alice))
+	same := rules.NewNetwork(owns(alice), owns(alice))
+	two := rules.NewNetwork(owns(alice), owns(bob))
+	if same.Len() != one.Len() {
+		t.Errorf("expected conditions on the same object to share nodes")
+	}
+	if two.Len() == one.Len() {
+		t.Errorf("expected conditions on different objects not to share nodes")
+	}
+}
+
+func benchmarkAccounts() []*account {
+	accts := make([]*account, 100)
+	for i := range accts {
+		accts[i] = &account{
+			Region:  regions[i%len(regions)],
+			Balance: float64(i * 37 % 2600),
+			Active:  i%5 != 0,
+		}
+	}
+	return accts
+}
+
+func BenchmarkNaiveMatch(b *testing.B) {
+	rs := accountRules(1000)
+	accts := benchmarkAccounts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := accts[i%len(accts)]
+		a.Balance = float64(i % 2600)
+		naiveMatches(b, rs, a)
+	}
+}
+
+func BenchmarkNetworkMatch(b *testing.B) {
+	ctx := context.Background()
+	rs := accountRules(1000)
+	accts := benchmarkAccounts()
+	n := rules.NewNetwork(rs...)
+	for _, a := range accts {
+		if err := n.Add(ctx, a); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := accts[i%len(accts)]
+		a.Balance = float64(i % 2600)
+		if err := n.Update(ctx, a, "Balance"); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := n.Matches(a); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
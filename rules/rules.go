@@ -0,0 +1,193 @@
+// Package rules evaluates "when condition then action" rules whose conditions
+// are expr BoolExprs.
+package rules
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/skillian/errors"
+	"github.com/skillian/expr"
+)
+
+// Rule is a named condition and the actions to perform when the condition is
+// true.
+type Rule struct {
+	// Name identifies the rule in traces and errors.
+	Name string
+
+	// Priority orders rules when a RuleSet's Strategy uses priorities.
+	// Rules with higher priorities are fired first.
+	Priority int
+
+	// FactType restricts the rule to facts of the given Go type.  If it is
+	// nil, the rule is checked against every fact.
+	FactType reflect.Type
+
+	// When is the rule's condition.  Its expressions can refer to the fact
+	// that the rule is evaluated against with Fact or Field.
+	When expr.BoolExpr
+
+	// Assign is evaluated when the rule fires.  Each assignment's value is
+	// evaluated before any of the targets are assigned.
+	Assign []Assignment
+
+	// Then is called after the assignments when the rule fires.
+	Then func(ctx context.Context, fact interface{}) error
+}
+
+// Match checks if the rule's condition is true for the fact.  Facts that are
+// not of the rule's FactType never match.
+func (r *Rule) Match(ctx context.Context, fact interface{}) (bool, error) {
+	if r.FactType != nil && reflect.TypeOf(fact) != r.FactType {
+		return false, nil
+	}
+	ok, err := expr.EvalBoolContext(WithFact(ctx, fact), r.When)
+	if err != nil {
+		return false, &RuleError{Rule: r.Name, Err: err}
+	}
+	return ok, nil
+}
+
+// Fire performs the rule's assignments and then calls its Then function.
+func (r *Rule) Fire(ctx context.Context, fact interface{}) error {
+	ctx = WithFact(ctx, fact)
+	values := make([]expr.Value, len(r.Assign))
+	for i, a := range r.Assign {
+		v, err := expr.EvalContext(ctx, a.Value)
+		if err != nil {
+			return &RuleError{Rule: r.Name, Err: err}
+		}
+		values[i] = v
+	}
+	for i, a := range r.Assign {
+		if err := a.assign(ctx, values[i]); err != nil {
+			return &RuleError{Rule: r.Name, Err: err}
+		}
+	}
+	if r.Then != nil {
+		if err := r.Then(ctx, fact); err != nil {
+			return &RuleError{Rule: r.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// String represents the rule as a string.
+func (r *Rule) String() string {
+	return fmt.Sprintf("rule %q when %v", r.Name, r.When)
+}
+
+// Assignment sets its Target to the result of its Value expression.
+type Assignment struct {
+	// Target is an expr.Attr or an expr.Var.
+	Target expr.Expr
+
+	// Value is the expression whose result is assigned to Target.
+	Value expr.ValueExpr
+}
+
+// Set creates an Assignment of the value to the target.
+func Set(target expr.Expr, value expr.ValueExpr) Assignment {
+	return Assignment{Target: target, Value: value}
+}
+
+func (a Assignment) assign(ctx context.Context, v expr.Value) error {
+	switch t := a.Target.(type) {
+	case expr.Attr:
+		return t.Assign(ctx, v)
+	case expr.Var:
+		return t.SetValue(v)
+	}
+	return errors.Errorf("cannot assign to %v (type: %T)", a.Target, a.Target)
+}
+
+// String represents the assignment as a string.
+func (a Assignment) String() string {
+	return fmt.Sprintf("%v = %v", a.Target, a.Value)
+}
+
+// RuleError wraps an error from evaluating or firing a rule.
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %q: %v", e.Rule, e.Err)
+}
+
+// Unwrap gets the error that the rule failed with.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+type factKey struct{}
+
+// WithFact returns a copy of ctx whose evaluations of Fact evaluate to fact.
+func WithFact(ctx context.Context, fact interface{}) context.Context {
+	return context.WithValue(ctx, factKey{}, factValue{fact})
+}
+
+// FactFromContext gets the fact associated with the context and whether
+// there is one.
+func FactFromContext(ctx context.Context) (interface{}, bool) {
+	fact, ok := ctx.Value(factKey{}).(factValue)
+	return fact.v, ok
+}
+
+// factValue wraps facts in the context so that a nil fact can be told apart
+// from no fact.
+type factValue struct {
+	v interface{}
+}
+
+// Fact is an expression that evaluates to the fact that the rules are being
+// evaluated against.
+type Fact struct{}
+
+// Copy the expression.
+func (f Fact) Copy(transformations ...expr.Mapper) expr.Expr {
+	return expr.ApplyMappers(f, transformations...)
+}
+
+// Eval the expression.
+func (f Fact) Eval() (interface{}, error) {
+	value, err := f.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (f Fact) EvalValue() (expr.Value, error) {
+	return expr.EvalContext(context.Background(), f)
+}
+
+// EvalValueContext evaluates the expression to the fact associated with the
+// context.
+func (f Fact) EvalValueContext(ctx context.Context) (expr.Value, error) {
+	fact, ok := FactFromContext(ctx)
+	if !ok {
+		return nil, errors.Errorf("no fact to evaluate %v against", f)
+	}
+	return expr.ValueOf(fact), nil
+}
+
+// String represents the expression as a string.
+func (f Fact) String() string {
+	return "fact"
+}
+
+// Field creates an expression that gets the named attribute of the fact.
+// Multiple names get nested attributes, e.g. Field("Customer", "Name") is
+// fact.Customer.Name.  Without any names, it gets the fact itself.
+func Field(names ...string) expr.ValueExpr {
+	var e expr.ValueExpr = Fact{}
+	for _, name := range names {
+		e = expr.Attr{ValueExpr: e, Name: expr.String(name)}
+	}
+	return e
+}
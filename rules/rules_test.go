@@ -0,0 +1,166 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/skillian/expr"
+	"github.com/skillian/expr/rules"
+)
+
+type customer struct {
+	Name string
+	VIP  bool
+}
+
+type order struct {
+	Customer customer
+	Total    float64
+	Discount float64
+	Tags     []string
+}
+
+func discountRules(strategy rules.Strategy) *rules.RuleSet {
+	return rules.NewRuleSet(
+		strategy,
+		&rules.Rule{
+			Name:     "big order",
+			Priority: 1,
+			When:     expr.Ge{rules.Field("Total"), expr.Float64(100)},
+			Assign: []rules.Assignment{
+				rules.Set(rules.Field("Discount"), expr.Float64(0.05)),
+			},
+		},
+		&rules.Rule{
+			Name:     "vip",
+			Priority: 2,
+			When:     expr.All{expr.Eq{rules.Field("Customer", "VIP"), expr.True}, expr.Gt{rules.Field("Total"), expr.Float64(0)}},
+			Assign: []rules.Assignment{
+				rules.Set(rules.Field("Discount"), expr.Float64(0.1)),
+			},
+		},
+		&rules.Rule{
+			Name: "tag",
+			When: expr.Lt{rules.Field("Discount"), expr.Float64(0.05)},
+			Then: func(ctx context.Context, fact interface{}) error {
+				o := fact.(*order)
+				o.Tags = append(o.Tags, "full price")
+				return nil
+			},
+		},
+	)
+}
+
+func TestRuleSetStrategies(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		strategy rules.Strategy
+		fired    []string
+		discount float64
+	}{
+		{rules.FirstMatch, []string{"big order"}, 0.05},
+		{rules.AllMatch, []string{"big order", "vip", "tag"}, 0.1},
+		{rules.PriorityMatch, []string{"vip", "big order", "tag"}, 0.05},
+		{rules.HighestPriority, []string{"vip"}, 0.1},
+	}
+	for _, tc := range tcs {
+		o := &order{Customer: customer{"Sean", true}, Total: 150}
+		trace, err := discountRules(tc.strategy).Eval(context.Background(), o)
+		if err != nil {
+			t.Errorf("%v: %v", tc.strategy, err)
+			continue
+		}
+		if names := trace.FiredNames(); !reflect.DeepEqual(names, tc.fired) {
+			t.Errorf("%v: fired %v (expected %v)", tc.strategy, names, tc.fired)
+		}
+		if o.Discount != tc.discount {
+			t.Errorf("%v: discount %v (expected %v)", tc.strategy, o.Discount, tc.discount)
+		}
+		for _, e := range trace.Entries {
+			if !e.Matched {
+				t.Errorf("%v: expected %q to match", tc.strategy, e.Rule.Name)
+			}
+		}
+	}
+}
+
+func TestRuleSetTrace(t *testing.T) {
+	t.Parallel()
+	o := &order{Customer: customer{"Ryan", false}, Total: 20}
+	trace, err := discountRules(rules.AllMatch).Eval(context.Background(), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := make([]bool, len(trace.Entries))
+	for i, e := range trace.Entries {
+		matched[i] = e.Matched
+	}
+	if !reflect.DeepEqual(matched, []bool{false, false, true}) {
+		t.Errorf("unexpected matches: %v", matched)
+	}
+	if !reflect.DeepEqual(o.Tags, []string{"full price"}) {
+		t.Errorf("unexpected tags: %v", o.Tags)
+	}
+	expect := "big order: not matched\nvip: not matched\ntag: fired\n"
+	if s := trace.String(); s != expect {
+		t.Errorf("unexpected trace:\n%s", s)
+	}
+}
+
+func TestRuleErrors(t *testing.T) {
+	t.Parallel()
+	var count expr.Int
+	rs := rules.NewRuleSet(
+		rules.AllMatch,
+		&rules.Rule{
+			Name: "count",
+			When: expr.True,
+			Assign: []rules.Assignment{
+				rules.Set(&count, expr.Add{&count, expr.Int(1)}),
+			},
+		},
+		&rules.Rule{
+			Name: "missing",
+			When: expr.Eq{rules.Field("Missing"), expr.Int(1)},
+		},
+	)
+	trace, err := rs.Eval(context.Background(), &order{})
+	var re *rules.RuleError
+	if !errors.As(err, &re) || re.Rule != "missing" {
+		t.Fatalf("expected *RuleError from %q but got %v", "missing", err)
+	}
+	var ua *expr.UnknownAttributeError
+	if !errors.As(err, &ua) {
+		t.Errorf("expected *UnknownAttributeError but got %v", err)
+	}
+	if len(trace.Fired) != 0 || count != 0 {
+		t.Errorf("expected no rules to fire but %v fired", trace.FiredNames())
+	}
+	if _, err := rules.Field("Total").EvalValue(); err == nil {
+		t.Errorf("expected evaluating a field without a fact to fail")
+	}
+	rs = rules.NewRuleSet(rules.AllMatch, rs.Rules()[0])
+	if _, err := rs.Eval(context.Background(), nil); err != nil || count != 1 {
+		t.Errorf("count -> %v (err: %v)", count, err)
+	}
+}
+
+func TestField(t *testing.T) {
+	t.Parallel()
+	o := &order{Customer: customer{Name: "bob"}}
+	ctx := rules.WithFact(context.Background(), o)
+	v, err := expr.EvalContext(ctx, rules.Field("Customer", "Name"))
+	if err != nil || v != expr.String("bob") {
+		t.Errorf("customer name -> %v (err: %v)", v, err)
+	}
+	v, err = expr.EvalContext(ctx, rules.Field())
+	if err != nil || v.Interface() != o {
+		t.Errorf("fact -> %v (err: %v)", v, err)
+	}
+	r := &rules.Rule{Name: "replace", When: expr.True, Assign: []rules.Assignment{rules.Set(rules.Field(), expr.ValueOf(&order{}))}}
+	if err := r.Fire(context.Background(), o); err == nil {
+		t.Errorf("expected assigning to the fact to fail")
+	}
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Strategy selects which of a RuleSet's matching rules are fired.
+type Strategy int
+
+const (
+	// FirstMatch fires the first matching rule in the order that the rules
+	// were added to the RuleSet.
+	FirstMatch Strategy = iota
+
+	// AllMatch fires every matching rule in the order that the rules were
+	// added to the RuleSet.
+	AllMatch
+
+	// PriorityMatch fires every matching rule from the highest Priority to
+	// the lowest.  Rules with the same Priority are fired in the order that
+	// they were added.
+	PriorityMatch
+
+	// HighestPriority fires only the matching rule with the highest
+	// Priority.  If more than one rule has that Priority, the first one
+	// added is fired.
+	HighestPriority
+)
+
+var strategyNames = [...]string{
+	FirstMatch:      "first match",
+	AllMatch:        "all match",
+	PriorityMatch:   "priority match",
+	HighestPriority: "highest priority",
+}
+
+func (s Strategy) String() string {
+	if s >= 0 && int(s) < len(strategyNames) {
+		return strategyNames[s]
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// RuleSet is a collection of rules that are evaluated together.
+type RuleSet struct {
+	// Strategy selects which of the matching rules are fired.
+	Strategy Strategy
+
+	rules []*Rule
+}
+
+// NewRuleSet creates a RuleSet of the given rules.
+func NewRuleSet(strategy Strategy, rules ...*Rule) *RuleSet {
+	rs := &RuleSet{Strategy: strategy}
+	rs.Add(rules...)
+	return rs
+}
+
+// Add rules to the RuleSet.
+func (rs *RuleSet) Add(rules ...*Rule) {
+	rs.rules = append(rs.rules, rules...)
+}
+
+// Rules gets the rules in the order that they were added.
+func (rs *RuleSet) Rules() []*Rule {
+	return append([]*Rule(nil), rs.rules...)
+}
+
+// Eval evaluates the rules against the fact.  Every rule's condition is
+// checked before any rule is fired so that the actions of one rule do not
+// affect which other rules are fired.  The returned Trace records the rules
+// that matched and fired up until the first error.
+func (rs *RuleSet) Eval(ctx context.Context, fact interface{}) (*Trace, error) {
+	trace := &Trace{}
+	var matched []int
+	for _, r := range rs.rules {
+		ok, err := r.Match(ctx, fact)
+		if err != nil {
+			return trace, err
+		}
+		if ok {
+			matched = append(matched, len(trace.Entries))
+		}
+		trace.Entries = append(trace.Entries, TraceEntry{Rule: r, Matched: ok})
+	}
+	if len(matched) == 0 {
+		return trace, nil
+	}
+	switch rs.Strategy {
+	case FirstMatch:
+		matched = matched[:1]
+	case PriorityMatch, HighestPriority:
+		sort.SliceStable(matched, func(i, j int) bool {
+			return trace.Entries[matched[i]].Rule.Priority > trace.Entries[matched[j]].Rule.Priority
+		})
+		if rs.Strategy == HighestPriority {
+			matched = matched[:1]
+		}
+	}
+	for _, i := range matched {
+		e := &trace.Entries[i]
+		if err := ctx.Err(); err != nil {
+			return trace, err
+		}
+		if err := e.Rule.Fire(ctx, fact); err != nil {
+			return trace, err
+		}
+		e.Fired = true
+		trace.Fired = append(trace.Fired, e.Rule)
+	}
+	return trace, nil
+}
+
+// Trace records the evaluation of a RuleSet.
+type Trace struct {
+	// Entries has an entry for each rule whose condition was checked, in
+	// the order that the rules were added to the RuleSet.
+	Entries []TraceEntry
+
+	// Fired are the rules that were fired in the order they were fired.
+	Fired []*Rule
+}
+
+// TraceEntry records the evaluation of a single rule.
+type TraceEntry struct {
+	Rule    *Rule
+	Matched bool
+	Fired   bool
+}
+
+// FiredNames gets the names of the fired rules in the order they were fired.
+func (t *Trace) FiredNames() []string {
+	names := make([]string, len(t.Fired))
+	for i, r := range t.Fired {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// String represents the trace with one line per rule.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	for _, e := range t.Entries {
+		state := "not matched"
+		switch {
+		case e.Fired:
+			state = "fired"
+		case e.Matched:
+			state = "matched"
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", e.Rule.Name, state)
+	}
+	return sb.String()
+}
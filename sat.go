@@ -0,0 +1,487 @@
+package expr
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/skillian/errors"
+)
+
+// Witness is an assignment of values that satisfies an expression.
+type Witness []WitnessValue
+
+// WitnessValue is the value assigned to an expression in a Witness.
+type WitnessValue struct {
+	// Expr is either an operand compared against constants (e.g. an Attr
+	// or a Var) or a boolean expression that the satisfiability check
+	// could not look into.
+	Expr Expr
+
+	// Value is the value that Expr must have.  Numbers are *Rationals and
+	// the values of Bool comparisons and opaque boolean expressions are
+	// Bools.
+	Value Value
+}
+
+// Lookup gets the value assigned to the expression that is the same as e
+// according to SameExpr.
+func (w Witness) Lookup(e Expr) (Value, bool) {
+	for _, wv := range w {
+		if SameExpr(wv.Expr, e) {
+			return wv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Satisfiable checks if there is an assignment of values that makes e true
+// and returns one if there is.
+//
+// Comparisons (Eq, Ne, Lt, Le, Gt and Ge) between an expression such as an
+// Attr or a Var and a numeric, string or Bool constant are reasoned about
+// exactly: The constraints on each expression are combined into an interval
+// that excludes the Ne constants.  Numbers are compared as rationals, so
+// x > 0 and x < 1 is satisfiable even if x can only hold integers.  Every
+// other boolean expression is treated as a proposition that can be either
+// true or false, so p and p == false is unsatisfiable.
+//
+// An error is returned instead of an answer if an expression is compared
+// with constants of different kinds (e.g. x == 1 and x == "a"), if a Bool
+// constant is ordered, or if a constant of any other type (e.g. a Time or a
+// NaN) is compared with an expression, because the comparisons could fail
+// when they are evaluated.
+//
+// e is expanded into disjunctive normal form, which can grow exponentially.
+func Satisfiable(e BoolExpr) (Witness, bool, error) {
+	return SatisfiableContext(context.Background(), e)
+}
+
+// SatisfiableContext checks if e is satisfiable like Satisfiable.  A
+// *LimitError is returned if e expands into more terms than the MaxClauses
+// of the context's Limits.
+func SatisfiableContext(ctx context.Context, e BoolExpr) (Witness, bool, error) {
+	limits, _ := LimitsFromContext(ctx)
+	terms, err := normalForm(e, limits.MaxClauses, true)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, term := range terms {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		w, ok, err := satisfyTerm(term)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return w, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// Implies checks if b is true whenever a is true.  It is reasoned about like
+// Satisfiable, so if b depends on propositions that a doesn't force, it is not
+// implied even if it is in practice, and comparisons that Satisfiable cannot
+// reason about result in an error.
+func Implies(a, b BoolExpr) (bool, error) {
+	return ImpliesContext(context.Background(), a, b)
+}
+
+// ImpliesContext checks if a implies b like Implies and SatisfiableContext.
+func ImpliesContext(ctx context.Context, a, b BoolExpr) (bool, error) {
+	_, ok, err := SatisfiableContext(ctx, All{a, Not{b}})
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+// Equivalent checks if a and b imply each other.
+func Equivalent(a, b BoolExpr) (bool, error) {
+	return EquivalentContext(context.Background(), a, b)
+}
+
+// EquivalentContext checks if a and b are equivalent like Equivalent and
+// SatisfiableContext.
+func EquivalentContext(ctx context.Context, a, b BoolExpr) (bool, error) {
+	ok, err := ImpliesContext(ctx, a, b)
+	if err != nil || !ok {
+		return false, err
+	}
+	return ImpliesContext(ctx, b, a)
+}
+
+// satisfyTerm gets a witness of a conjunction of literals if it is
+// satisfiable.  Propositions are subjects that equal true or false.
+func satisfyTerm(term []BoolExpr) (Witness, bool, error) {
+	var subjects []*subject
+	for _, lit := range term {
+		rel, e, v, ok, err := relationOf(lit)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			var prop Expr = lit
+			positive := true
+			if n, ok := lit.(Not); ok {
+				prop, positive = n[0], false
+			}
+			if ok, known := constRelation(prop); known {
+				if ok != positive {
+					return nil, false, nil
+				}
+				continue
+			}
+			rel, e, v = relEq, prop, Bool(positive)
+		}
+		var s *subject
+		for _, x := range subjects {
+			if SameExpr(x.e, e) {
+				s = x
+				break
+			}
+		}
+		if s == nil {
+			s = &subject{e: e}
+			subjects = append(subjects, s)
+		}
+		if err := s.add(rel, v); err != nil {
+			annotate(err, lit, nil)
+			return nil, false, err
+		}
+	}
+	w := make(Witness, 0, len(subjects))
+	for _, s := range subjects {
+		v, ok := s.in.witness(s.order)
+		if !ok {
+			return nil, false, nil
+		}
+		w = append(w, WitnessValue{s.e, v})
+	}
+	return w, true, nil
+}
+
+// relation is a comparison of an expression with a constant.
+type relation int
+
+const (
+	relEq relation = iota
+	relNe
+	relLt
+	relLe
+	relGt
+	relGe
+)
+
+// flip gets the relation with its operands swapped (e.g. 1 < x is x > 1).
+func (r relation) flip() relation {
+	switch r {
+	case relLt:
+		return relGt
+	case relLe:
+		return relGe
+	case relGt:
+		return relLt
+	case relGe:
+		return relLe
+	}
+	return r
+}
+
+// operandsOf gets the relation of a comparison expression and its operands.
+func operandsOf(e Expr) (relation, binary, bool) {
+	switch e := e.(type) {
+	case Eq:
+		return relEq, binary(e), true
+	case Ne:
+		return relNe, binary(e), true
+	case Lt:
+		return relLt, binary(e), true
+	case Le:
+		return relLe, binary(e), true
+	case Gt:
+		return relGt, binary(e), true
+	case Ge:
+		return relGe, binary(e), true
+	}
+	return 0, binary{}, false
+}
+
+// relationOf gets the relation of e if it compares an expression with a
+// numeric, string or Bool constant.  Numeric constants are returned as
+// *Rationals.  An error is returned if the expression is compared with any
+// other constant.
+func relationOf(e Expr) (rel relation, subject Expr, v Value, ok bool, err error) {
+	rel, b, ok := operandsOf(e)
+	if !ok {
+		return 0, nil, nil, false, nil
+	}
+	switch {
+	case IsConst(b[1]) && !IsConst(b[0]):
+		subject, v = b[0], b[1].(Value)
+	case IsConst(b[0]) && !IsConst(b[1]):
+		rel, subject, v = rel.flip(), b[1], b[0].(Value)
+	default:
+		return 0, nil, nil, false, nil
+	}
+	switch x := v.(type) {
+	case String, Bool:
+		return rel, subject, x, true, nil
+	case Number:
+		var r big.Rat
+		if !ratOf(x, &r) {
+			break
+		}
+		return rel, subject, (*Rational)(&r), true, nil
+	}
+	return 0, nil, nil, false, errors.Errorf(
+		"cannot reason about comparisons with %v (type: %T) in %v",
+		v, v, StringifyExpr(e, false))
+}
+
+// ratOf gets the value of the number as a rational unless it is a NaN or
+// an infinity.
+func ratOf(n Number, r *big.Rat) bool {
+	var f float64
+	switch n := n.(type) {
+	case Float32:
+		f = float64(n)
+	case Float64:
+		f = float64(n)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return false
+	}
+	n.Rat(r)
+	return true
+}
+
+// constRelation evaluates e if it is a comparison of two constants.
+func constRelation(e Expr) (ok, known bool) {
+	_, b, isrel := operandsOf(e)
+	if !isrel || !IsConst(b[0]) || !IsConst(b[1]) {
+		return false, false
+	}
+	ok, err := e.(BoolExpr).EvalBool()
+	return ok, err == nil
+}
+
+// subject is an expression compared against constants.
+type subject struct {
+	e     Expr
+	order order
+	in    interval
+
+	// first is the first constant that e was compared with.
+	first Value
+}
+
+// add constrains the subject by its relation to v.  Comparing a number with
+// a string or a Bool fails when it is evaluated, so a *NotComparableError is
+// returned if v has a different order than the earlier constants.
+func (s *subject) add(rel relation, v Value) error {
+	var o order
+	switch v.(type) {
+	case String:
+		o = stringOrder{}
+	case Bool:
+		if rel != relEq && rel != relNe {
+			return notComparable(False, True, nil)
+		}
+		o = boolOrder{}
+	default:
+		o = ratOrder{}
+	}
+	if s.order == nil {
+		s.order, s.first = o, v
+	} else if s.order != o {
+		return notComparable(s.first, v, nil)
+	}
+	s.in.add(o, rel, v)
+	return nil
+}
+
+// interval holds the values that satisfy a subject's constraints.  A nil
+// bound is unbounded.
+type interval struct {
+	lo, hi         Value
+	loOpen, hiOpen bool
+	excluded       []Value
+}
+
+// order compares the values in an interval and generates candidate
+// witnesses for it.
+type order interface {
+	cmp(a, b Value) int
+
+	// candidate gets the kth candidate value of the interval.  The
+	// candidates must be distinct and if there are values in the
+	// interval, enough of them must be in it to find one that isn't
+	// excluded.
+	candidate(in *interval, k int) (Value, bool)
+}
+
+func (in *interval) add(o order, rel relation, v Value) {
+	switch rel {
+	case relNe:
+		in.excluded = append(in.excluded, v)
+		return
+	case relEq:
+		in.lower(o, v, false)
+		in.upper(o, v, false)
+	case relLt:
+		in.upper(o, v, true)
+	case relLe:
+		in.upper(o, v, false)
+	case relGt:
+		in.lower(o, v, true)
+	case relGe:
+		in.lower(o, v, false)
+	}
+}
+
+// lower raises the lower bound to v if it is tighter.
+func (in *interval) lower(o order, v Value, open bool) {
+	if in.lo == nil {
+		in.lo, in.loOpen = v, open
+		return
+	}
+	if c := o.cmp(v, in.lo); c > 0 || (c == 0 && open) {
+		in.lo, in.loOpen = v, open
+	}
+}
+
+// upper lowers the upper bound to v if it is tighter.
+func (in *interval) upper(o order, v Value, open bool) {
+	if in.hi == nil {
+		in.hi, in.hiOpen = v, open
+		return
+	}
+	if c := o.cmp(v, in.hi); c < 0 || (c == 0 && open) {
+		in.hi, in.hiOpen = v, open
+	}
+}
+
+// contains checks if v satisfies the interval's constraints.
+func (in *interval) contains(o order, v Value) bool {
+	if in.lo != nil {
+		if c := o.cmp(v, in.lo); c < 0 || (c == 0 && in.loOpen) {
+			return false
+		}
+	}
+	if in.hi != nil {
+		if c := o.cmp(v, in.hi); c > 0 || (c == 0 && in.hiOpen) {
+			return false
+		}
+	}
+	for _, x := range in.excluded {
+		if o.cmp(v, x) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// witness gets a value in the interval.  Because each excluded value can
+// only rule out one candidate, checking one more candidate than there are
+// excluded values (and the closed bounds) is enough.
+func (in *interval) witness(o order) (Value, bool) {
+	for k := 0; k < len(in.excluded)+3; k++ {
+		v, ok := o.candidate(in, k)
+		if !ok {
+			break
+		}
+		if in.contains(o, v) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// ratOrder orders *Rationals.
+type ratOrder struct{}
+
+func (ratOrder) cmp(a, b Value) int {
+	return (*big.Rat)(a.(*Rational)).Cmp((*big.Rat)(b.(*Rational)))
+}
+
+// candidate gets the closed bounds first.  After them, if the interval is
+// bounded on both sides, the candidates approach the lower bound by halving
+// the distance to it.  Otherwise, they are integer steps away from the bound
+// or from 0.
+func (o ratOrder) candidate(in *interval, k int) (Value, bool) {
+	var closed []Value
+	if in.lo != nil && !in.loOpen {
+		closed = append(closed, in.lo)
+	}
+	if in.hi != nil && !in.hiOpen {
+		closed = append(closed, in.hi)
+	}
+	if k < len(closed) {
+		return closed[k], true
+	}
+	j := int64(k - len(closed))
+	r := new(big.Rat)
+	switch {
+	case in.lo != nil && in.hi != nil:
+		if o.cmp(in.lo, in.hi) >= 0 {
+			return nil, false
+		}
+		lo := (*big.Rat)(in.lo.(*Rational))
+		r.Sub((*big.Rat)(in.hi.(*Rational)), lo)
+		r.Quo(r, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(j+1))))
+		r.Add(r, lo)
+	case in.lo != nil:
+		r.Add((*big.Rat)(in.lo.(*Rational)), big.NewRat(j+1, 1))
+	case in.hi != nil:
+		r.Sub((*big.Rat)(in.hi.(*Rational)), big.NewRat(j+1, 1))
+	default:
+		r.SetInt64(j)
+	}
+	return (*Rational)(r), true
+}
+
+// stringOrder orders Strings by their bytes.
+type stringOrder struct{}
+
+func (stringOrder) cmp(a, b Value) int {
+	return strings.Compare(string(a.(String)), string(b.(String)))
+}
+
+// candidate gets the kth smallest string allowed by the lower bound.  The
+// smallest string greater than s is s + "\x00".
+func (stringOrder) candidate(in *interval, k int) (Value, bool) {
+	var s string
+	if in.lo != nil {
+		s = string(in.lo.(String))
+		if in.loOpen {
+			s += "\x00"
+		}
+	}
+	return String(s + strings.Repeat("\x00", k)), true
+}
+
+// boolOrder orders false before true.  Only Eq and Ne relate Bools, so the
+// order only has to tell them apart.
+type boolOrder struct{}
+
+func (boolOrder) cmp(a, b Value) int {
+	switch x, y := a.(Bool), b.(Bool); {
+	case x == y:
+		return 0
+	case bool(y):
+		return -1
+	}
+	return 1
+}
+
+// candidate gets false and then true.
+func (boolOrder) candidate(in *interval, k int) (Value, bool) {
+	if k > 1 {
+		return nil, false
+	}
+	return Bool(k == 1), true
+}
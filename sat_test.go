@@ -0,0 +1,175 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/skillian/expr"
+)
+
+func TestSatisfiable(t *testing.T) {
+	t.Parallel()
+	x := expr.RationalZero().Type().Var().(*expr.RationalVar)
+	y := expr.RationalZero().Type().Var().(*expr.RationalVar)
+	var s expr.String
+	var p expr.Bool
+	tcs := []struct {
+		e      expr.BoolExpr
+		expect bool
+	}{
+		{expr.True, true},
+		{expr.False, false},
+		{expr.All{expr.Gt{x, expr.Int(0)}, expr.Lt{x, expr.Int(10)}}, true},
+		{expr.All{expr.Gt{x, expr.Int(10)}, expr.Lt{x, expr.Int(0)}}, false},
+		{expr.All{expr.Ge{x, expr.Int(5)}, expr.Le{x, expr.Float64(5)}}, true},
+		{expr.All{expr.Gt{x, expr.Int(5)}, expr.Le{x, expr.Int(5)}}, false},
+		{expr.All{expr.Gt{x, expr.Int(0)}, expr.Lt{x, expr.Int(1)}}, true},
+		{expr.All{expr.Eq{x, expr.Int(1)}, expr.Ne{x, expr.Int(1)}}, false},
+		{expr.All{expr.Eq{x, expr.Int(1)}, expr.Eq{expr.Int(2), x}}, false},
+		{expr.All{expr.Lt{expr.Int(3), x}, expr.Lt{x, expr.Int(4)}, expr.Ne{x, expr.Float64(3.5)}}, true},
+		{
+			expr.All{
+				expr.Ge{x, expr.Int(1)}, expr.Le{x, expr.Int(3)},
+				expr.Ne{x, expr.Int(1)}, expr.Ne{x, expr.Int(2)}, expr.Ne{x, expr.Int(3)},
+			},
+			true,
+		},
+		{expr.All{expr.Ne{x, expr.Int(0)}, expr.Ne{x, expr.Int(1)}, expr.Ne{x, expr.Int(2)}}, true},
+		{expr.All{expr.Gt{x, expr.Int(0)}, expr.Lt{x, expr.Int(10)}, expr.Gt{y, expr.Int(3)}}, true},
+		{expr.Any{expr.All{expr.Gt{x, expr.Int(5)}, expr.Lt{x, expr.Int(5)}}, expr.Eq{y, expr.Int(3)}}, true},
+		{expr.Not{expr.Any{expr.Lt{x, expr.Int(5)}, expr.Ge{x, expr.Int(5)}}}, false},
+		{expr.All{expr.Gt{&s, expr.String("a")}, expr.Lt{&s, expr.String("b")}}, true},
+		{expr.All{expr.Gt{&s, expr.String("a")}, expr.Lt{&s, expr.String("a\x00")}}, false},
+		{expr.All{expr.Ge{&s, expr.String("a")}, expr.Lt{&s, expr.String("a\x00")}}, true},
+		{expr.All{expr.Lt{&s, expr.String("\x00")}, expr.Ne{&s, expr.String("")}}, false},
+		{expr.All{expr.Gt{&s, expr.String("a")}, expr.Ne{&s, expr.String("a\x00")}, expr.Ne{&s, expr.String("a\x00\x00")}}, true},
+		{expr.All{expr.Eq{&s, expr.String("a")}, expr.Ne{&s, expr.String("b")}}, true},
+		{expr.All{&p, expr.Not{&p}}, false},
+		{expr.All{expr.Eq{&p, expr.True}, expr.Eq{&p, expr.False}}, false},
+		{expr.All{&p, expr.Eq{&p, expr.False}}, false},
+		{expr.All{expr.Ne{&p, expr.True}, expr.Not{&p}}, true},
+		{expr.All{expr.Ne{&p, expr.True}, expr.Ne{expr.False, &p}}, false},
+		{expr.All{&p, expr.Gt{x, expr.Int(1)}, expr.Not{expr.All{&p, expr.Ge{x, expr.Int(1)}}}}, false},
+		{expr.Any{&p, expr.Gt{expr.Int(1), expr.Int(2)}}, true},
+		{expr.All{expr.Not{&p}, expr.Gt{expr.Int(1), expr.Int(2)}}, false},
+	}
+	for _, tc := range tcs {
+		w, ok, err := expr.Satisfiable(tc.e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.expect {
+			t.Errorf("%v: expected satisfiable to be %v", tc.e, tc.expect)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, wv := range w {
+			if err := wv.Expr.(expr.Var).SetValue(wv.Value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if actual, err := tc.e.EvalBool(); err != nil || !actual {
+			t.Errorf("%v: witness %v evaluated to %v (err: %v)", tc.e, w, actual, err)
+		}
+	}
+}
+
+func TestImplies(t *testing.T) {
+	t.Parallel()
+	person := expr.ValueOf(&struct {
+		Age  int
+		Name string
+	}{})
+	age := expr.Attr{person, "Age"}
+	name := expr.Attr{person, "Name"}
+	adult := expr.Ge{age, expr.Int(18)}
+	tcs := []struct {
+		a, b       expr.BoolExpr
+		implies    bool
+		equivalent bool
+	}{
+		{adult, expr.Gt{age, expr.Int(17)}, true, false},
+		{expr.Gt{age, expr.Int(17)}, adult, false, false},
+		{adult, expr.Not{expr.Lt{age, expr.Int(18)}}, true, true},
+		{expr.All{adult, expr.Eq{name, expr.String("bob")}}, adult, true, false},
+		{adult, expr.Any{adult, expr.Eq{name, expr.String("bob")}}, true, false},
+		{
+			expr.Any{expr.Lt{age, expr.Int(13)}, expr.Gt{age, expr.Int(19)}},
+			expr.Not{expr.All{expr.Ge{age, expr.Int(13)}, expr.Le{age, expr.Int(19)}}},
+			true, true,
+		},
+		{expr.Eq{age, expr.Float64(21)}, expr.All{adult, expr.Ne{age, expr.Int(20)}}, true, false},
+		{adult, expr.Eq{name, expr.String("bob")}, false, false},
+	}
+	for _, tc := range tcs {
+		implies, err := expr.Implies(tc.a, tc.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if implies != tc.implies {
+			t.Errorf("%v implies %v: %v (expected %v)", tc.a, tc.b, implies, tc.implies)
+		}
+		equivalent, err := expr.Equivalent(tc.a, tc.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if equivalent != tc.equivalent {
+			t.Errorf("%v equivalent to %v: %v (expected %v)", tc.a, tc.b, equivalent, tc.equivalent)
+		}
+	}
+}
+
+func TestSatisfiableErrors(t *testing.T) {
+	t.Parallel()
+	x := expr.RationalZero().Type().Var().(*expr.RationalVar)
+	var s expr.String
+	var p expr.Bool
+	for _, e := range []expr.BoolExpr{
+		expr.All{expr.Eq{&s, expr.String("a")}, expr.Ne{&s, expr.Int(1)}},
+		expr.All{expr.Eq{x, expr.Int(1)}, expr.Eq{x, expr.True}},
+		expr.All{&p, expr.Gt{&p, expr.Int(0)}},
+		expr.Lt{&p, expr.True},
+	} {
+		_, _, err := expr.Satisfiable(e)
+		var nc *expr.NotComparableError
+		if !errors.As(err, &nc) {
+			t.Errorf("%v: expected *NotComparableError but got %v", e, err)
+		}
+	}
+	if _, _, err := expr.Satisfiable(expr.Eq{x, expr.Float64(math.NaN())}); err == nil {
+		t.Error("expected an error from comparing with NaN")
+	}
+	if _, err := expr.Implies(expr.Eq{x, expr.Int(1)}, expr.Eq{x, expr.String("x")}); err == nil {
+		t.Error("expected an error from comparing a number and a string")
+	}
+}
+
+func TestSatisfiableLimit(t *testing.T) {
+	t.Parallel()
+	x := expr.RationalZero().Type().Var().(*expr.RationalVar)
+	e := make(expr.All, 0, 8)
+	for i := 0; i < 8; i++ {
+		e = append(e, expr.Any{expr.Lt{x, expr.Int(i)}, expr.Gt{x, expr.Int(i + 100)}})
+	}
+	ctx := expr.WithLimits(context.Background(), expr.Limits{MaxClauses: 100})
+	_, _, err := expr.SatisfiableContext(ctx, e)
+	var le *expr.LimitError
+	if !errors.As(err, &le) || le.Kind != expr.ClauseLimit {
+		t.Errorf("expected *LimitError but got %v", err)
+	}
+	w, ok, err := expr.Satisfiable(e)
+	if err != nil || !ok {
+		t.Fatalf("expected %v to be satisfiable (err: %v)", e, err)
+	}
+	v, ok := w.Lookup(x)
+	if !ok {
+		t.Fatalf("%v has no value for %v", w, x)
+	}
+	if lt, err := (expr.Lt{v, expr.Int(0)}).EvalBool(); err != nil || !lt {
+		t.Errorf("unexpected witness %v", w)
+	}
+}
@@ -1,6 +1,7 @@
 package expr
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/skillian/errors"
@@ -28,15 +29,10 @@ func (t settype) Zero() Value {
 	return s
 }
 
-// Var is supposed to create a Set variable.  I can't think of the right way for
-// this to work, so this just panics.  Let me know if you have a use case.
+// Var creates a SetVar whose elements are variables of the set type's
+// element types.
 func (t settype) Var() Var {
-	panic(
-		errors.Errorf(
-			"Set cannot yet be made into a Var.  Let skillian know that you " +
-				"got this error message and how. Hopefully after I see some " +
-				"use cases, I can come up with an expected way for this to " +
-				"operate."))
+	return newSetVar(t)
 }
 
 // Copy the Set and the inner expressions.
@@ -51,12 +47,14 @@ func (s Set) Copy(transformations ...Mapper) Expr {
 // EvalValue evaluates each of the expressions in the set and stores the results
 // in a ValueSet and returns it.
 func (s Set) EvalValue() (result Value, err error) {
+	return EvalContext(context.Background(), s)
+}
+
+// EvalValueContext evaluates the Set into a ValueSet under ctx.
+func (s Set) EvalValueContext(ctx context.Context) (result Value, err error) {
 	v := make(ValueSet, len(s))
-	for i, valueExpr := range s {
-		v[i], err = valueExpr.EvalValue()
-		if err != nil {
-			return nil, err
-		}
+	if err = evalValuesContext(ctx, s, v); err != nil {
+		return nil, err
 	}
 	return v, nil
 }
@@ -76,14 +74,17 @@ func (s Set) Type() Type {
 }
 
 // ValueSet can be treated as an actual Value (all of its inner expressions)
-// are Values).
+// are Values).  The elements of a new SetVar whose element Types aren't known
+// until evaluation are nil.
 type ValueSet []Value
 
-// Copy the ValueSet
+// Copy the ValueSet.  nil elements are kept as they are.
 func (s ValueSet) Copy(transformations ...Mapper) Expr {
 	s2 := make(ValueSet, len(s))
 	for i, e := range s {
-		s2[i] = e.Copy(transformations...).(Value)
+		if e != nil {
+			s2[i] = e.Copy(transformations...).(Value)
+		}
 	}
 	return ApplyMappers(s2, transformations...)
 }
@@ -97,6 +98,9 @@ func (s ValueSet) EvalValue() (Value, error) {
 func (s ValueSet) Eval() (result interface{}, err error) {
 	results := make([]interface{}, len(s))
 	for i, value := range s {
+		if value == nil {
+			continue
+		}
 		results[i], err = value.Eval()
 		if err != nil {
 			return nil, err
@@ -109,33 +113,78 @@ func (s ValueSet) Eval() (result interface{}, err error) {
 func (s ValueSet) Interface() interface{} {
 	results := make([]interface{}, len(s))
 	for i, value := range s {
-		results[i] = value.Interface()
+		if value != nil {
+			results[i] = value.Interface()
+		}
 	}
 	return results
 }
 
-// Cmp allows sets to be compared to one another.
+// Cmp allows sets to be compared to one another.  Sets are ordered like
+// tuples: their elements are compared in order and the first difference
+// decides the result.  If one set is a prefix of the other, the shorter set
+// sorts first.  Nested sets are compared the same way.
 func (s ValueSet) Cmp(v Value) (int, error) {
-	if s2, ok := v.(ValueSet); ok {
-		len1 := len(s)
-		len2 := len(s2)
-		minlen := Min(len1, len2)
-		for i, value := range s[:minlen] {
-			cmp, err := Cmp(value, s2[i])
-			if err != nil {
-				return 0, errors.ErrorfWithCause(
-					err,
-					"Failed to compare element %v to %v at index %d",
-					value, s2[i], i)
-			}
-			if cmp != 0 {
-				return cmp, nil
-			}
+	return s.cmp(nil, v)
+}
+
+// cmp compares s to v under the Policy p.
+func (s ValueSet) cmp(p *Policy, v Value) (int, error) {
+	s2, ok := v.(ValueSet)
+	if !ok {
+		return 0, notComparable(s, v, nil)
+	}
+	minlen := Min(len(s), len(s2))
+	for i, value := range s[:minlen] {
+		cmp, err := cmpValues(p, value, s2[i])
+		if err != nil {
+			return 0, notComparable(s, v, errors.ErrorfWithCause(
+				err,
+				"Failed to compare element %v to %v at index %d",
+				value, s2[i], i))
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+	// Every element compared equal so the shorter set sorts first.
+	switch {
+	case len(s) < len(s2):
+		return -1, nil
+	case len(s) > len(s2):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Equal checks if s has the same number of elements as another ValueSet and
+// each of its elements is equal to the other set's element at the same index.
+func (s ValueSet) Equal(v Value) (bool, error) {
+	return s.equal(nil, v)
+}
+
+// equal checks if s is equal to v under the Policy p.
+func (s ValueSet) equal(p *Policy, v Value) (bool, error) {
+	s2, ok := v.(ValueSet)
+	if !ok {
+		return false, notComparable(s, v, nil)
+	}
+	if len(s) != len(s2) {
+		return false, nil
+	}
+	for i, value := range s {
+		equal, err := equalValues(p, value, s2[i])
+		if err != nil {
+			return false, notComparable(s, v, errors.ErrorfWithCause(
+				err,
+				"Failed to compare element %v to %v at index %d",
+				value, s2[i], i))
+		}
+		if !equal {
+			return false, nil
 		}
-		// Every element compared equal
-		return 0, nil
 	}
-	return 0, errors.Errorf("cannot compare %v to %v", s, v)
+	return true, nil
 }
 
 // Type gets the type of this set.
@@ -163,15 +212,135 @@ func Max(a, b int) int {
 	return b
 }
 
-// TypesOf Takes a slice of values and gets their expression Types.
+// TypesOf Takes a slice of values and gets their expression Types.  The Type
+// of an expression that doesn't know its Type until it is evaluated is the
+// Type of any Value.
 func TypesOf(values []ValueExpr) []Type {
 	types := make([]Type, len(values))
 	for i, value := range values {
-		if tve, ok := value.(TypedValueExpr); ok {
-			types[i] = tve.Type()
-		} else {
-			types[i] = dynamictype{Type: reflect.TypeOf(value)}
-		}
+		types[i] = typeOfExpr(value)
 	}
 	return types
 }
+
+// typeOfExpr gets the Type of the value that e evaluates to.
+func typeOfExpr(e ValueExpr) Type {
+	switch e := e.(type) {
+	case TypedValueExpr:
+		return e.Type()
+	case Value:
+		if tv, ok := ValueOf(e.Interface()).(TypedValueExpr); ok {
+			return tv.Type()
+		}
+		return dynamictype{Type: reflect.TypeOf(e.Interface())}
+	case BoolExpr:
+		return BoolType
+	}
+	return anytype{}
+}
+
+// anytype is the Type of an expression whose Type isn't known until it is
+// evaluated.
+type anytype struct{}
+
+// Zero returns nil because there is no zero value of any Type.
+func (t anytype) Zero() Value {
+	return nil
+}
+
+// Var creates a variable that can hold any Value.
+func (t anytype) Var() Var {
+	return &anyVar{}
+}
+
+// anyVar is a variable that can hold any Value.
+type anyVar struct {
+	value Value
+}
+
+func (v *anyVar) Value() Value {
+	return v.value
+}
+
+func (v *anyVar) SetValue(x Value) error {
+	v.value = x
+	return nil
+}
+
+// SetVar is a variable that holds a ValueSet.  Each element of the set is
+// held by a variable of the element's Type so values assigned to the set are
+// converted element-wise.
+type SetVar struct {
+	typ  settype
+	vars []Var
+}
+
+func newSetVar(t settype) *SetVar {
+	vars := make([]Var, len(t.types))
+	for i, typ := range t.types {
+		vars[i] = typ.Var()
+	}
+	return &SetVar{typ: t, vars: vars}
+}
+
+// Copy copies the SetVar.  Copy panics if the transformations turn the
+// elements into values that the SetVar cannot hold.
+func (s *SetVar) Copy(transformations ...Mapper) Expr {
+	copied := s.Value().Copy(transformations...)
+	vs, ok := copied.(ValueSet)
+	if !ok {
+		return copied
+	}
+	s2 := newSetVar(s.typ)
+	if err := s2.SetValue(vs); err != nil {
+		panic(errors.ErrorfWithCause(err, "failed to copy %v", s))
+	}
+	return ApplyMappers(s2, transformations...)
+}
+
+// Eval evaluates the SetVar into a []interface{}.
+func (s *SetVar) Eval() (interface{}, error) {
+	return s.Value().Eval()
+}
+
+// EvalValue evaluates the SetVar to its ValueSet value.
+func (s *SetVar) EvalValue() (Value, error) {
+	return s.Value(), nil
+}
+
+// Type gets the SetVar's set Type.
+func (s *SetVar) Type() Type {
+	return s.typ
+}
+
+// Value gets the variable's elements' values as a ValueSet.
+func (s *SetVar) Value() Value {
+	vs := make(ValueSet, len(s.vars))
+	for i, v := range s.vars {
+		vs[i] = v.Value()
+	}
+	return vs
+}
+
+// SetValue sets each of the variable's elements from the elements of the
+// ValueSet, v.  v must have the same number of elements as the variable.
+func (s *SetVar) SetValue(v Value) error {
+	vs, ok := v.(ValueSet)
+	if !ok {
+		return conversionError(v, s.typ, nil)
+	}
+	if len(vs) != len(s.vars) {
+		return conversionError(v, s.typ, errors.Errorf(
+			"expected %d values but got %d", len(s.vars), len(vs)))
+	}
+	vars := make([]Var, len(s.vars))
+	for i, value := range vs {
+		vars[i] = s.typ.types[i].Var()
+		if err := vars[i].SetValue(value); err != nil {
+			return conversionError(v, s.typ, errors.ErrorfWithCause(
+				err, "failed to set element %d to %v", i, value))
+		}
+	}
+	s.vars = vars
+	return nil
+}
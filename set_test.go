@@ -0,0 +1,189 @@
+package expr_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/skillian/expr"
+)
+
+// tuple generates ValueSets for property tests.  Elements at even indexes
+// are Ints and elements at odd indexes are nested tuples so that any two
+// tuples can be compared.
+type tuple struct {
+	expr.ValueSet
+}
+
+func (tuple) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(tuple{genTuple(r, 2)})
+}
+
+func genTuple(r *rand.Rand, depth int) expr.ValueSet {
+	s := make(expr.ValueSet, r.Intn(4))
+	for i := range s {
+		if i%2 == 1 {
+			if depth == 0 {
+				s[i] = expr.ValueSet{}
+			} else {
+				s[i] = genTuple(r, depth-1)
+			}
+			continue
+		}
+		s[i] = expr.Int(r.Intn(3))
+	}
+	return s
+}
+
+func cmpTuples(t *testing.T, a, b tuple) int {
+	t.Helper()
+	cmp, err := a.Cmp(b.ValueSet)
+	if err != nil {
+		t.Fatalf("%v <=> %v: %v", a.ValueSet, b.ValueSet, err)
+	}
+	return cmp
+}
+
+func TestValueSetOrdering(t *testing.T) {
+	t.Parallel()
+	cfg := &quick.Config{MaxCount: 500}
+	reflexive := func(a tuple) bool {
+		return cmpTuples(t, a, a) == 0
+	}
+	antisymmetric := func(a, b tuple) bool {
+		return cmpTuples(t, a, b) == -cmpTuples(t, b, a)
+	}
+	transitive := func(a, b, c tuple) bool {
+		ab, bc := cmpTuples(t, a, b), cmpTuples(t, b, c)
+		if ab <= 0 && bc <= 0 {
+			return cmpTuples(t, a, c) <= 0
+		}
+		if ab >= 0 && bc >= 0 {
+			return cmpTuples(t, a, c) >= 0
+		}
+		return true
+	}
+	consistent := func(a, b tuple) bool {
+		eq, err := a.Equal(b.ValueSet)
+		return err == nil && eq == (cmpTuples(t, a, b) == 0)
+	}
+	prefix := func(a tuple, n uint8) bool {
+		b := tuple{a.ValueSet[:int(n)%(len(a.ValueSet)+1)]}
+		if len(b.ValueSet) == len(a.ValueSet) {
+			return cmpTuples(t, b, a) == 0
+		}
+		return cmpTuples(t, b, a) < 0
+	}
+	for name, f := range map[string]interface{}{
+		"reflexive":     reflexive,
+		"antisymmetric": antisymmetric,
+		"transitive":    transitive,
+		"consistent":    consistent,
+		"prefix":        prefix,
+	} {
+		if err := quick.Check(f, cfg); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestValueSetCmp(t *testing.T) {
+	t.Parallel()
+	one, two := expr.Int(1), expr.Int(2)
+	tcs := []struct {
+		left, right expr.ValueSet
+		expect      int
+	}{
+		{expr.ValueSet{one}, expr.ValueSet{one, two}, -1},
+		{expr.ValueSet{one, two}, expr.ValueSet{one}, 1},
+		{expr.ValueSet{}, expr.ValueSet{}, 0},
+		{expr.ValueSet{two}, expr.ValueSet{one, two}, 1},
+		{expr.ValueSet{one, expr.ValueSet{one}}, expr.ValueSet{one, expr.ValueSet{one, one}}, -1},
+		{expr.ValueSet{one, expr.ValueSet{two}}, expr.ValueSet{one, expr.ValueSet{one, one}}, 1},
+		{expr.ValueSet{one, expr.String("a")}, expr.ValueSet{one, expr.String("b")}, -1},
+		{expr.ValueSet{expr.String("ab")}, expr.ValueSet{expr.String("a"), one}, 1},
+		{expr.ValueSet{expr.String("a"), two}, expr.ValueSet{expr.String("a"), one}, 1},
+	}
+	for _, tc := range tcs {
+		cmp, err := expr.Cmp(tc.left, tc.right)
+		if err != nil {
+			t.Errorf("%v <=> %v: %v", tc.left, tc.right, err)
+			continue
+		}
+		if cmp != tc.expect {
+			t.Errorf("%v <=> %v -> %d (expected %d)", tc.left, tc.right, cmp, tc.expect)
+		}
+	}
+	var nc *expr.NotComparableError
+	if _, err := expr.Cmp(expr.ValueSet{one}, expr.ValueSet{expr.ValueSet{one}}); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+	if _, err := expr.Cmp(expr.ValueSet{expr.String("1")}, expr.ValueSet{one}); !errors.As(err, &nc) {
+		t.Errorf("expected *NotComparableError but got %v", err)
+	}
+}
+
+func TestSetVar(t *testing.T) {
+	t.Parallel()
+	x := expr.Int(0)
+	s := expr.Set{expr.Int64(1), expr.String("a"), expr.Add{&x, expr.Int(1)}}
+	types := expr.TypesOf(s)
+	if types[0] != expr.Int64Type || types[1] != expr.StringType {
+		t.Errorf("unexpected element types: %v", types)
+	}
+	v := s.Type().Var()
+	if err := v.SetValue(expr.ValueSet{expr.Int(5), expr.String("b"), expr.Float64(1.5)}); err != nil {
+		t.Fatal(err)
+	}
+	expect := expr.ValueSet{expr.Int64(5), expr.String("b"), expr.Float64(1.5)}
+	if eq, err := expr.EqualValues(v.Value(), expect); err != nil || !eq {
+		t.Errorf("expected %v but got %v (err: %v)", expect, v.Value(), err)
+	}
+	if v.Value().(expr.ValueSet)[0] != expr.Int64(5) {
+		t.Errorf("expected element 0 to be converted to Int64 but got %T", v.Value().(expr.ValueSet)[0])
+	}
+	var ce *expr.ConversionError
+	if err := v.SetValue(expr.ValueSet{expr.Int(1)}); !errors.As(err, &ce) {
+		t.Errorf("expected *ConversionError but got %v", err)
+	}
+	if err := v.SetValue(expr.ValueSet{expr.String("x"), expr.String("b"), expr.Int(1)}); !errors.As(err, &ce) {
+		t.Errorf("expected *ConversionError but got %v", err)
+	}
+	if eq, err := expr.EqualValues(v.Value(), expect); err != nil || !eq {
+		t.Errorf("failed SetValue modified the variable: %v", v.Value())
+	}
+}
+
+func TestSetVarUntypedElements(t *testing.T) {
+	t.Parallel()
+	a := expr.Attr{expr.ValueOf(&struct{ Name string }{"a"}), "Name"}
+	v := expr.Set{a, expr.Int(1)}.Type().Var().(*expr.SetVar)
+	if vs := v.Value().(expr.ValueSet); vs[0] != nil || vs[1] != expr.Int(0) {
+		t.Errorf("expected [nil 0] but got %v", vs)
+	}
+	copied, ok := v.Copy().(*expr.SetVar)
+	if !ok {
+		t.Fatalf("expected *SetVar but got %T", v.Copy())
+	}
+	if _, err := copied.Eval(); err != nil {
+		t.Error(err)
+	}
+	if err := copied.SetValue(expr.ValueSet{expr.String("b"), expr.Int(2)}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err, _ := recover().(error)
+		var ce *expr.ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("expected a *ConversionError panic but got %v", err)
+		}
+	}()
+	copied.Copy(func(e expr.Expr) expr.Expr {
+		if e == expr.Int(2) {
+			return expr.String("c")
+		}
+		return e
+	})
+}
@@ -1,6 +1,9 @@
 package expr
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // String wraps a Go string value.
 type String string
@@ -40,6 +43,22 @@ func (s String) Type() Type {
 	return StringType
 }
 
+// Equal checks if s is equal to another String.
+func (s String) Equal(v Value) (bool, error) {
+	if o, ok := v.(String); ok {
+		return s == o, nil
+	}
+	return false, notComparable(s, v, nil)
+}
+
+// Cmp compares s to another String by their bytes.
+func (s String) Cmp(v Value) (int, error) {
+	if o, ok := v.(String); ok {
+		return strings.Compare(string(s), string(o)), nil
+	}
+	return 0, notComparable(s, v, nil)
+}
+
 // Value of the expression.
 func (s *String) Value() Value {
 	return *s
@@ -47,11 +66,12 @@ func (s *String) Value() Value {
 
 // SetValue of the expression.
 func (s *String) SetValue(v Value) error {
-	if v, ok := v.(String); ok {
-		*s = v
-		return nil
+	c, err := Convert(v, StringType)
+	if err != nil {
+		return err
 	}
-	return TypeErrorFromExpectedAndActual(s, v)
+	*s = c.(String)
+	return nil
 }
 
 // StringifyExpr represents the expression as a string. If the expression is
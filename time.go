@@ -0,0 +1,538 @@
+package expr
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/skillian/errors"
+)
+
+// Time wraps a Go time.Time value.  Times are compared by the instant they
+// represent regardless of their location.
+type Time time.Time
+
+type timetype struct{}
+
+// TimeType is the expr.Type of a Time.
+var TimeType Type = timetype{}
+
+// Zero gets the zero Time.
+func (t timetype) Zero() Value {
+	return Time{}
+}
+
+// Var creates a Time variable.
+func (t timetype) Var() Var {
+	return new(Time)
+}
+
+// timeLayouts are the ISO-8601 layouts accepted by ParseTime.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses an ISO-8601 timestamp such as "2018-06-01T12:30:00Z" or
+// a date such as "2018-06-01".  Timestamps without a time zone offset are in
+// UTC.
+func ParseTime(s string) (Time, error) {
+	return ParseTimeIn(s, time.UTC)
+}
+
+// ParseTimeIn parses an ISO-8601 timestamp like ParseTime but timestamps
+// without a time zone offset are in loc.
+func ParseTimeIn(s string, loc *time.Location) (Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return Time(t), nil
+		}
+	}
+	return Time{}, errors.Errorf("invalid timestamp %q", s)
+}
+
+// Copy the expression.
+func (t Time) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(t, transformations...)
+}
+
+// Cmp compares t to another Time.
+func (t Time) Cmp(v Value) (int, error) {
+	u, ok := v.(Time)
+	if !ok {
+		return 0, notComparable(t, v, nil)
+	}
+	switch {
+	case time.Time(t).Before(time.Time(u)):
+		return -1, nil
+	case time.Time(t).After(time.Time(u)):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Eval the expression.
+func (t Time) Eval() (interface{}, error) {
+	return t.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (t Time) EvalValue() (Value, error) {
+	return t, nil
+}
+
+// Interface gets the Time as a time.Time.
+func (t Time) Interface() interface{} {
+	return time.Time(t)
+}
+
+// Type gets the expression type of t: TimeType.
+func (t Time) Type() Type {
+	return TimeType
+}
+
+// In gets the same instant as t in loc.
+func (t Time) In(loc *time.Location) Time {
+	return Time(time.Time(t).In(loc))
+}
+
+// EvalAdd adds a Duration to t.
+func (t Time) EvalAdd(v Value) (Value, error) {
+	if d, ok := v.(Duration); ok {
+		return Time(time.Time(t).Add(time.Duration(d))), nil
+	}
+	return nil, typeMismatch("add", t, v)
+}
+
+// EvalSubtract subtracts a Duration from t or gets the Duration between t
+// and another Time.
+func (t Time) EvalSubtract(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Duration:
+		return Time(time.Time(t).Add(-time.Duration(v))), nil
+	case Time:
+		return Duration(time.Time(t).Sub(time.Time(v))), nil
+	}
+	return nil, typeMismatch("subtract", t, v)
+}
+
+// GetAttr gets a component of the Time in its location.  Year, Month, Day,
+// Hour, Minute, Second, Nanosecond, Weekday (with Sunday = 0) and YearDay
+// are Ints, Unix is an Int64, Zone is the abbreviated time zone name,
+// Location is the name of the location and Date, UTC and Local are Times.
+func (t Time) GetAttr(name String) (Value, error) {
+	tt := time.Time(t)
+	switch name {
+	case "Year":
+		return Int(tt.Year()), nil
+	case "Month":
+		return Int(tt.Month()), nil
+	case "Day":
+		return Int(tt.Day()), nil
+	case "Hour":
+		return Int(tt.Hour()), nil
+	case "Minute":
+		return Int(tt.Minute()), nil
+	case "Second":
+		return Int(tt.Second()), nil
+	case "Nanosecond":
+		return Int(tt.Nanosecond()), nil
+	case "Weekday":
+		return Int(tt.Weekday()), nil
+	case "YearDay":
+		return Int(tt.YearDay()), nil
+	case "Unix":
+		return Int64(tt.Unix()), nil
+	case "Zone":
+		zone, _ := tt.Zone()
+		return String(zone), nil
+	case "Location":
+		return String(tt.Location().String()), nil
+	case "Date":
+		y, m, d := tt.Date()
+		return Time(time.Date(y, m, d, 0, 0, 0, 0, tt.Location())), nil
+	case "UTC":
+		return Time(tt.UTC()), nil
+	case "Local":
+		return Time(tt.Local()), nil
+	}
+	return nil, unknownAttribute(t, string(name))
+}
+
+// String represents the Time as an ISO-8601 timestamp.
+func (t Time) String() string {
+	return time.Time(t).Format(time.RFC3339Nano)
+}
+
+// Value of the Time variable.
+func (t *Time) Value() Value {
+	return *t
+}
+
+// SetValue sets the Time variable to v converted into a Time.
+func (t *Time) SetValue(v Value) error {
+	c, err := Convert(v, TimeType)
+	if err != nil {
+		return err
+	}
+	*t = c.(Time)
+	return nil
+}
+
+// Duration wraps a Go time.Duration value.
+type Duration time.Duration
+
+type durationtype struct{}
+
+// DurationType is the expr.Type of a Duration.
+var DurationType Type = durationtype{}
+
+// Zero gets the zero Duration.
+func (t durationtype) Zero() Value {
+	return Duration(0)
+}
+
+// Var creates a Duration variable.
+func (t durationtype) Var() Var {
+	return new(Duration)
+}
+
+// durationUnit is a unit of a duration string that is converted into a
+// multiple of a time.ParseDuration unit.
+type durationUnit struct {
+	unit   string
+	factor time.Duration
+}
+
+// durationUnits are the units that ParseDuration accepts in addition to the
+// units accepted by time.ParseDuration.
+var durationUnits = map[string]durationUnit{
+	"d": {"h", 24},
+	"w": {"h", 7 * 24},
+}
+
+// isoDurationUnits are the designators of ISO-8601 durations.  Designators
+// after the "T" are stored in lower case.
+var isoDurationUnits = map[byte]durationUnit{
+	'W': {"h", 7 * 24},
+	'D': {"h", 24},
+	'h': {"h", 1},
+	'm': {"m", 1},
+	's': {"s", 1},
+}
+
+// ParseDuration parses a duration.  It accepts the same syntax as
+// time.ParseDuration with the additional units "d" (24 hours) and "w" (7
+// days), e.g. "30d" or "1w2d12h".  It also accepts ISO-8601 durations such
+// as "P30D" or "PT1H30M" without years or months which don't have a fixed
+// length.  A bare "0" is accepted like it is by time.ParseDuration.
+func ParseDuration(s string) (Duration, error) {
+	body := strings.TrimLeft(s, "+-")
+	if strings.HasPrefix(body, "P") {
+		return parseISODuration(s, body[1:])
+	}
+	if len(s)-len(body) > 1 || body == "" {
+		return 0, errors.Errorf("invalid duration %q", s)
+	}
+	if body == "0" {
+		return 0, nil
+	}
+	var total time.Duration
+	for rest := body; rest != ""; {
+		i := strings.IndexFunc(rest, isNotDecimal)
+		if i <= 0 {
+			return 0, errors.Errorf("invalid duration %q", s)
+		}
+		j := strings.IndexFunc(rest[i:], func(r rune) bool { return !isNotDecimal(r) })
+		if j < 0 {
+			j = len(rest) - i
+		}
+		unit, ok := durationUnits[rest[i:i+j]]
+		if !ok {
+			unit = durationUnit{rest[i : i+j], 1}
+		}
+		var err error
+		if total, err = addDuration(total, rest[:i], unit); err != nil {
+			return 0, errors.ErrorfWithCause(err, "invalid duration %q", s)
+		}
+		rest = rest[i+j:]
+	}
+	return signedDuration(s, total), nil
+}
+
+// parseISODuration parses the body of an ISO-8601 duration such as "P1DT12H"
+// after its "P".
+func parseISODuration(s, body string) (Duration, error) {
+	if len(s)-len(body) > 2 || body == "" || strings.HasSuffix(body, "T") {
+		return 0, errors.Errorf("invalid duration %q", s)
+	}
+	var total time.Duration
+	inTime := false
+	for body != "" {
+		if body[0] == 'T' && !inTime {
+			inTime = true
+			body = body[1:]
+			continue
+		}
+		i := strings.IndexFunc(body, isNotDecimal)
+		if i <= 0 {
+			return 0, errors.Errorf("invalid duration %q", s)
+		}
+		designator := body[i]
+		if inTime {
+			designator = byte(unicode.ToLower(rune(designator)))
+		} else if designator == 'Y' || designator == 'M' {
+			return 0, errors.Errorf(
+				"invalid duration %q: years and months do not have a fixed length", s)
+		}
+		unit, ok := isoDurationUnits[designator]
+		if !ok || (!inTime && unicode.IsLower(rune(designator))) {
+			return 0, errors.Errorf("invalid duration %q", s)
+		}
+		var err error
+		if total, err = addDuration(total, body[:i], unit); err != nil {
+			return 0, errors.ErrorfWithCause(err, "invalid duration %q", s)
+		}
+		body = body[i+1:]
+	}
+	return signedDuration(s, total), nil
+}
+
+func isNotDecimal(r rune) bool {
+	return (r < '0' || r > '9') && r != '.'
+}
+
+// addDuration adds number of unit to total.
+func addDuration(total time.Duration, number string, unit durationUnit) (time.Duration, error) {
+	d, err := time.ParseDuration(number + unit.unit)
+	if err != nil {
+		return 0, err
+	}
+	if d > (math.MaxInt64-total)/unit.factor {
+		return 0, errors.Errorf("duration out of range")
+	}
+	return total + d*unit.factor, nil
+}
+
+// signedDuration applies the sign of the duration string s to total.
+func signedDuration(s string, total time.Duration) Duration {
+	if strings.HasPrefix(s, "-") {
+		return Duration(-total)
+	}
+	return Duration(total)
+}
+
+// Copy the expression.
+func (d Duration) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(d, transformations...)
+}
+
+// Cmp compares d to another Duration.
+func (d Duration) Cmp(v Value) (int, error) {
+	e, ok := v.(Duration)
+	if !ok {
+		return 0, notComparable(d, v, nil)
+	}
+	switch {
+	case d < e:
+		return -1, nil
+	case d > e:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Eval the expression.
+func (d Duration) Eval() (interface{}, error) {
+	return d.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (d Duration) EvalValue() (Value, error) {
+	return d, nil
+}
+
+// Interface gets the Duration as a time.Duration.
+func (d Duration) Interface() interface{} {
+	return time.Duration(d)
+}
+
+// Type gets the expression type of d: DurationType.
+func (d Duration) Type() Type {
+	return DurationType
+}
+
+// EvalAdd adds another Duration to d or adds d to a Time.
+func (d Duration) EvalAdd(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Duration:
+		return d + v, nil
+	case Time:
+		return v.EvalAdd(d)
+	}
+	return nil, typeMismatch("add", d, v)
+}
+
+// EvalSubtract subtracts another Duration from d.
+func (d Duration) EvalSubtract(v Value) (Value, error) {
+	if e, ok := v.(Duration); ok {
+		return d - e, nil
+	}
+	return nil, typeMismatch("subtract", d, v)
+}
+
+// GetAttr gets the Duration in different units:  Days, Hours, Minutes and
+// Seconds are Float64s and Milliseconds, Microseconds and Nanoseconds are
+// Int64s.
+func (d Duration) GetAttr(name String) (Value, error) {
+	td := time.Duration(d)
+	switch name {
+	case "Days":
+		return Float64(td.Hours() / 24), nil
+	case "Hours":
+		return Float64(td.Hours()), nil
+	case "Minutes":
+		return Float64(td.Minutes()), nil
+	case "Seconds":
+		return Float64(td.Seconds()), nil
+	case "Milliseconds":
+		return Int64(td / time.Millisecond), nil
+	case "Microseconds":
+		return Int64(td / time.Microsecond), nil
+	case "Nanoseconds":
+		return Int64(td), nil
+	}
+	return nil, unknownAttribute(d, string(name))
+}
+
+// String represents the Duration the same way as time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Value of the Duration variable.
+func (d *Duration) Value() Value {
+	return *d
+}
+
+// SetValue sets the Duration variable to v converted into a Duration.
+func (d *Duration) SetValue(v Value) error {
+	c, err := Convert(v, DurationType)
+	if err != nil {
+		return err
+	}
+	*d = c.(Duration)
+	return nil
+}
+
+type clockKey struct{}
+
+// WithClock returns a copy of ctx whose evaluations of Now get the current
+// time from clock instead of time.Now.
+func WithClock(ctx context.Context, clock func() time.Time) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFromContext gets the clock associated with the context.  The default
+// is time.Now.
+func ClockFromContext(ctx context.Context) func() time.Time {
+	if clock, ok := ctx.Value(clockKey{}).(func() time.Time); ok {
+		return clock
+	}
+	return time.Now
+}
+
+// Now is an expression that evaluates to the current Time.
+type Now struct{}
+
+// Copy the expression.
+func (n Now) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(n, transformations...)
+}
+
+// Eval the expression.
+func (n Now) Eval() (interface{}, error) {
+	value, err := n.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (n Now) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), n)
+}
+
+// EvalValueContext evaluates the expression to the current time according to
+// the context's clock.
+func (n Now) EvalValueContext(ctx context.Context) (Value, error) {
+	return Time(ClockFromContext(ctx)()), nil
+}
+
+// String represents the expression as a string.
+func (n Now) String() string {
+	return "now"
+}
+
+// InZone is a binary expression that converts the Time on its left side into
+// the time zone named by the String on its right side, e.g.
+// "America/New_York" or "UTC".
+type InZone binaryValue
+
+// Copy the expression.
+func (z InZone) Copy(transformations ...Mapper) Expr {
+	return ApplyMappers(InZone(binaryValue(z).copy(transformations...)), transformations...)
+}
+
+// Eval the expression.
+func (z InZone) Eval() (interface{}, error) {
+	value, err := z.EvalValue()
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// EvalValue evaluates the expression to a value.
+func (z InZone) EvalValue() (Value, error) {
+	return EvalContext(context.Background(), z)
+}
+
+// EvalValueContext evaluates the expression to a value under ctx.
+func (z InZone) EvalValueContext(ctx context.Context) (Value, error) {
+	var operands [2]Value
+	err := evalValuesContext(ctx, z[:], operands[:])
+	if err != nil {
+		return nil, err
+	}
+	t, tok := operands[0].(Time)
+	name, nok := operands[1].(String)
+	if !tok || !nok {
+		return nil, typeMismatch("convert time zone of", operands[0], operands[1])
+	}
+	loc, err := time.LoadLocation(string(name))
+	if err != nil {
+		return nil, errors.ErrorfWithCause(err, "unknown time zone %q", name)
+	}
+	return t.In(loc), nil
+}
+
+// Left side of the binary expression.
+func (z InZone) Left() Expr {
+	return z[0]
+}
+
+// Right side of the binary expression.
+func (z InZone) Right() Expr {
+	return z[1]
+}
+
+// String represents the expression as a string.
+func (z InZone) String() string {
+	return stringifyBinaryInfixHelper(z, "in zone")
+}
@@ -0,0 +1,101 @@
+package expr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skillian/expr"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		s      string
+		expect time.Duration
+		ok     bool
+	}{
+		{"30d", 30 * 24 * time.Hour, true},
+		{"1w2d12h", 9*24*time.Hour + 12*time.Hour, true},
+		{"1.5d", 36 * time.Hour, true},
+		{"-90m", -90 * time.Minute, true},
+		{"P30D", 30 * 24 * time.Hour, true},
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1WT0.5S", 7*24*time.Hour + 500*time.Millisecond, true},
+		{"-P1D", -24 * time.Hour, true},
+		{"P1M", 0, false},
+		{"P1Y", 0, false},
+		{"PT", 0, false},
+		{"P1H", 0, false},
+		{"0", 0, true},
+		{"-0", 0, true},
+		{"00", 0, false},
+		{"30", 0, false},
+		{"30x", 0, false},
+		{"", 0, false},
+	}
+	for _, tc := range tcs {
+		d, err := expr.ParseDuration(tc.s)
+		if !tc.ok {
+			if err == nil {
+				t.Errorf("expected %q to fail but got %v", tc.s, d)
+			}
+			continue
+		}
+		if err != nil || time.Duration(d) != tc.expect {
+			t.Errorf("%q -> %v (err: %v) (expected %v)", tc.s, d, err, tc.expect)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+	now, err := expr.ParseTime("2018-06-01T12:30:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := expr.WithClock(context.Background(), func() time.Time { return time.Time(now) })
+	month, _ := expr.ParseDuration("30d")
+	fact := expr.ValueOf(struct {
+		CreatedAt time.Time
+		TTL       time.Duration
+	}{time.Date(2018, 5, 20, 0, 0, 0, 0, time.UTC), time.Hour})
+	created := expr.Attr{ValueExpr: fact, Name: "CreatedAt"}
+	day, err := expr.ParseTime("2018-06-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcs := []expr.BoolExpr{
+		expr.Gt{created, expr.Sub{expr.Now{}, month}},
+		expr.Lt{expr.Sub{expr.Now{}, created}, month},
+		expr.Eq{expr.Add{created, month}, expr.Add{month, created}},
+		expr.Eq{expr.Attr{ValueExpr: created, Name: "Month"}, expr.Int(5)},
+		expr.Eq{expr.Attr{ValueExpr: created, Name: "Weekday"}, expr.Int(time.Sunday)},
+		expr.Eq{expr.Attr{ValueExpr: expr.Now{}, Name: "Date"}, day},
+		expr.Gt{expr.Attr{ValueExpr: fact, Name: "TTL"}, expr.Duration(time.Minute)},
+		expr.Eq{expr.InZone{expr.Now{}, expr.String("Asia/Tokyo")}, expr.Now{}},
+		expr.Eq{
+			expr.Attr{ValueExpr: expr.InZone{expr.Now{}, expr.String("Asia/Tokyo")}, Name: "Hour"},
+			expr.Int(21),
+		},
+	}
+	for _, tc := range tcs {
+		ok, err := expr.EvalBoolContext(ctx, tc)
+		if err != nil {
+			if _, missing := time.LoadLocation("Asia/Tokyo"); missing != nil {
+				continue
+			}
+			t.Errorf("%v: %v", tc, err)
+			continue
+		}
+		if !ok {
+			t.Errorf("expected %v to be true", tc)
+		}
+	}
+	if _, err := (expr.Add{now, now}).EvalValue(); err == nil {
+		t.Errorf("expected adding two Times to fail")
+	}
+	if _, err := (expr.InZone{now, expr.String("Nowhere/Special")}).EvalValue(); err == nil {
+		t.Errorf("expected unknown time zone to fail")
+	}
+}
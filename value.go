@@ -2,6 +2,7 @@ package expr
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/skillian/errors"
 )
@@ -48,8 +49,12 @@ type VarExpr interface {
 
 // ValueOf works similiarly to reflect.ValueOf except there are Value
 // specializations for some of Go's built in types.  If there is no
-// specialization, a fallback dynamic value type is returned.
+// specialization, a fallback dynamic value type is returned.  Nil pointers
+// are always wrapped in dynamic values.
 func ValueOf(v interface{}) Value {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return Dynamic(rv)
+	}
 	switch v := v.(type) {
 	case Value:
 		return v.Copy().(Value)
@@ -61,6 +66,83 @@ func ValueOf(v interface{}) Value {
 		*b = Bool(*v)
 		return b
 
+	case string:
+		return String(v)
+	case *string:
+		s := new(String)
+		*s = String(*v)
+		return s
+
+	case int:
+		return Int(v)
+	case *int:
+		i := new(Int)
+		*i = Int(*v)
+		return i
+
+	case int8:
+		return Int8(v)
+	case *int8:
+		i := new(Int8)
+		*i = Int8(*v)
+		return i
+
+	case int16:
+		return Int16(v)
+	case *int16:
+		i := new(Int16)
+		*i = Int16(*v)
+		return i
+
+	case int32:
+		return Int32(v)
+	case *int32:
+		i := new(Int32)
+		*i = Int32(*v)
+		return i
+
+	case int64:
+		return Int64(v)
+	case *int64:
+		i := new(Int64)
+		*i = Int64(*v)
+		return i
+
+	case uint:
+		return Uint(v)
+	case *uint:
+		i := new(Uint)
+		*i = Uint(*v)
+		return i
+
+	case uint8:
+		return Uint8(v)
+	case *uint8:
+		i := new(Uint8)
+		*i = Uint8(*v)
+		return i
+
+	case uint16:
+		return Uint16(v)
+	case *uint16:
+		i := new(Uint16)
+		*i = Uint16(*v)
+		return i
+
+	case uint32:
+		return Uint32(v)
+	case *uint32:
+		i := new(Uint32)
+		*i = Uint32(*v)
+		return i
+
+	case uint64:
+		return Uint64(v)
+	case *uint64:
+		i := new(Uint64)
+		*i = Uint64(*v)
+		return i
+
 	case float32:
 		return Float32(v)
 	case *float32:
@@ -75,12 +157,33 @@ func ValueOf(v interface{}) Value {
 		*f = Float64(*v)
 		return f
 
-	case int:
-		return Int(v)
-	case *int:
-		i := new(Int)
-		*i = Int(*v)
-		return i
+	case complex64:
+		return Complex64(v)
+	case *complex64:
+		c := new(Complex64)
+		*c = Complex64(*v)
+		return c
+
+	case complex128:
+		return Complex128(v)
+	case *complex128:
+		c := new(Complex128)
+		*c = Complex128(*v)
+		return c
+
+	case time.Time:
+		return Time(v)
+	case *time.Time:
+		t := new(Time)
+		*t = Time(*v)
+		return t
+
+	case time.Duration:
+		return Duration(v)
+	case *time.Duration:
+		d := new(Duration)
+		*d = Duration(*v)
+		return d
 
 	default:
 		return Dynamic(reflect.ValueOf(v))